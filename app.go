@@ -28,16 +28,27 @@ func getVCSRevision() string {
 
 // App is our application instance.
 type App struct {
-	config  Config
-	logger  *slog.Logger
-	subber  *Subber
-	ws      *WebServer
-	version string
+	config     Config
+	configFile string
+	// load reads the configuration again, see reload.
+	load     func() (Config, error)
+	logger   *slog.Logger
+	logLevel *slog.LevelVar
+	subbers  []*Subber
+	ws       *WebServer
+	version  string
 }
 
 // NewApp creates an instance of our application, based on the supplied args and output locations.
+// The import subcommand runs to completion, returning no App.
 func NewApp(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) (*App, error) {
-	logHandler := slog.NewTextHandler(stderr, nil).
+	if len(args) > 1 && args[1] == importCommand {
+		return nil, runImport(args[2:], stdin, stdout, stderr)
+	}
+
+	// the level is set once the configuration is loaded.
+	logLevel := new(slog.LevelVar)
+	logHandler := slog.NewTextHandler(stderr, &slog.HandlerOptions{Level: logLevel}).
 		WithAttrs(
 			[]slog.Attr{slog.String("version", getVCSRevision())},
 		)
@@ -45,7 +56,15 @@ func NewApp(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer)
 	logger.Info("starting gosubs")
 
 	fs := flag.NewFlagSet("gosubs", flag.ContinueOnError)
-	configFile := fs.String("configFile", "config.json", "json file to read configuration from")
+	configFile := fs.String("configFile", envOr(os.LookupEnv, "CONFIG_FILE", "config.json"), "json, yaml or toml file to read configuration from, chosen by extension")
+	stateDir := fs.String("stateDir", envOr(os.LookupEnv, "STATE_DIR", "state"), "directory to save game state to, empty to keep state in memory only")
+	address := fs.String("address", "", "address to listen on, overrides config file")
+	port := fs.Int("port", defaultHTTPPort, "port to listen on, overrides config file")
+	tlsCertFile := fs.String("tlsCertFile", "", "PEM certificate file to serve HTTPS with, overrides config file")
+	tlsKeyFile := fs.String("tlsKeyFile", "", "PEM key file to serve HTTPS with, overrides config file")
+	tlsSelfSigned := fs.Bool("tlsSelfSigned", false, "serve HTTPS with a generated self-signed certificate, overrides config file")
+	var level slog.Level
+	fs.TextVar(&level, "logLevel", slog.LevelInfo, "minimum level logged, such as debug or warn, overrides config file")
 	showVersion := fs.Bool("version", false, "show version and exit")
 
 	if err := fs.Parse(args[1:]); err != nil {
@@ -57,45 +76,130 @@ func NewApp(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer)
 		return nil, nil
 	}
 
-	config := DefaultConfiguration()
+	// load reads the config file, overridden by GOSUBS_* environment variables
+	// and then flags set on the command line, on start up and on reload.
+	load := func() (Config, error) {
+		config := DefaultConfiguration()
 
-	if _, err := os.Stat(*configFile); err == nil {
-		f, err := os.Open(*configFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read config file: %w", err)
+		if _, err := os.Stat(*configFile); err == nil {
+			config, err = loadConfigFile(*configFile)
+			if err != nil {
+				return Config{}, fmt.Errorf("failed to load config: %w", err)
+			}
+
+			logger.Info("loaded configuration from file", "file", *configFile)
 		}
 
-		config, err = loadConfig(f)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load config: %w", err)
+		if err := config.applyEnv(os.LookupEnv); err != nil {
+			return Config{}, fmt.Errorf("failed to load environment: %w", err)
 		}
 
-		if err := f.Close(); err != nil {
-			return nil, fmt.Errorf("failed to close config file: %w", err)
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "address":
+				config.Server.Address = *address
+			case "port":
+				config.Server.Port = *port
+			case "tlsCertFile":
+				config.Server.TLSCertFile = *tlsCertFile
+			case "tlsKeyFile":
+				config.Server.TLSKeyFile = *tlsKeyFile
+			case "tlsSelfSigned":
+				config.Server.TLSSelfSigned = *tlsSelfSigned
+			case "logLevel":
+				config.LogLevel = level
+			}
+		})
+
+		if err := config.Validate(); err != nil {
+			return Config{}, err
 		}
 
-		logger.Info("loaded configuration from file", "file", *configFile)
+		return config, nil
 	}
 
-	subber := NewSubber(
-		logger.WithGroup("subber"),
-		config.Players,
-	)
+	config, err := load()
+	if err != nil {
+		return nil, err
+	}
+
+	logLevel.Set(config.LogLevel)
+
+	tlsConfig, err := newTLSConfig(config.Server, *stateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	sharer, err := newSharer(*stateDir)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := config.AllTeams()
+	if len(teams) == 0 {
+		logger.Warn("no teams configured, see config_example.json")
+	}
+
+	// state was saved to one file before there were teams.
+	if *stateDir != "" && len(teams) == 1 {
+		moved, err := migrateStateFile(*stateDir, teams[0].Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if moved {
+			logger.Info("moved state to the team's state file", "team", teams[0].Name, "from", legacyStateFile)
+		}
+	}
+
+	subbers := make([]*Subber, 0, len(teams))
+	seen := make(map[string]bool)
+
+	for _, team := range teams {
+		if seen[team.Name] {
+			return nil, fmt.Errorf("duplicate team name: %q", team.Name)
+		}
+
+		seen[team.Name] = true
+
+		var store Store = NewMemoryStore()
+		if *stateDir != "" {
+			store = NewFileStore(teamStateFile(*stateDir, team.Name))
+		}
+
+		subber, err := NewSubber(
+			logger.WithGroup("subber").With("team", team.Name),
+			store,
+			team,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		subbers = append(subbers, subber)
+	}
 
 	ws, err := NewWebServer(
 		logger.WithGroup("webserver"),
-		subber,
+		config.Server,
+		config.Auth,
+		tlsConfig,
+		sharer,
+		subbers,
 	)
 	if err != nil {
 		return nil, err
 	}
 
 	app := &App{
-		config:  config,
-		logger:  logger,
-		subber:  subber,
-		ws:      ws,
-		version: getVCSRevision(),
+		config:     config,
+		configFile: *configFile,
+		load:       load,
+		logger:     logger,
+		logLevel:   logLevel,
+		subbers:    subbers,
+		ws:         ws,
+		version:    getVCSRevision(),
 	}
 
 	return app, nil
@@ -122,6 +226,18 @@ func (app *App) Run(ctx context.Context) error {
 		return nil
 	})
 
+	// apply changes to the config file
+	g.Go(func() error {
+		return app.watchConfig(gctx)
+	})
+
+	// pause games when a period's time is up
+	for _, subber := range app.subbers {
+		g.Go(func() error {
+			return subber.RunClock(gctx)
+		})
+	}
+
 	// start HTTP server
 	g.Go(func() error {
 		err := app.ws.Run(gctx)
@@ -0,0 +1,370 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// importCommand is the subcommand importing a roster from CSV, such as
+// `gosubs import -team tigers roster.csv`.
+const importCommand = "import"
+
+// runImport merges the players of a CSV file into the config file, printing
+// what changed and the rows skipped.
+func runImport(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	fs := flag.NewFlagSet("gosubs import", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gosubs import [flags] roster.csv, or - to read standard input")
+		fs.PrintDefaults()
+	}
+
+	configFile := fs.String("configFile", envOr(os.LookupEnv, "CONFIG_FILE", "config.json"), "json file to merge players into, or yaml or toml file with -dryRun")
+	team := fs.String("team", "", "team to merge players into, may be omitted when there is only one")
+	name := fs.String("name", "", "comma separated columns joined to make each player's name, such as `First Name,Last Name`")
+	number := fs.String("number", "", "column of each player's number")
+	position := fs.String("position", "", "column of each player's preferred position")
+	availability := fs.String("availability", "", "column of each player's availability, such as absent or injured")
+	dryRun := fs.Bool("dryRun", false, "print the merged players instead of writing the config file")
+
+	if err := fs.Parse(args); err != nil {
+		return fmt.Errorf("failed to parse args: %w", err)
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+
+		return errors.New("expected one csv file to import")
+	}
+
+	columns := ImportColumns{
+		Name:         splitColumns(*name),
+		Number:       *number,
+		Position:     *position,
+		Availability: *availability,
+	}
+
+	input := stdin
+
+	if path := fs.Arg(0); path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("failed to open csv file: %w", err)
+		}
+		defer f.Close()
+
+		input = f
+	}
+
+	imported, problems, err := readRoster(input, columns)
+	if err != nil {
+		return err
+	}
+
+	format, err := configFormat(*configFile)
+	if err != nil {
+		return err
+	}
+
+	// comments and layout of yaml and toml files would be lost rewriting them.
+	if format != configFormatJSON && !*dryRun {
+		return fmt.Errorf("only json config files can be updated, use -dryRun to print the %s players", format)
+	}
+
+	raw, err := readRawConfig(*configFile, format)
+	if err != nil {
+		return err
+	}
+
+	roster, positions, err := raw.players(*team)
+	if err != nil {
+		return err
+	}
+
+	merged, result := mergeRoster(roster, imported, positions)
+	result.Problems = append(problems, result.Problems...)
+
+	for _, problem := range result.Problems {
+		fmt.Fprintf(stdout, "skipped %s\n", problem)
+	}
+
+	fmt.Fprintln(stdout, result.Summary())
+
+	if *dryRun {
+		b, err := encodePlayers(merged, format)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(stdout, string(b))
+
+		return nil
+	}
+
+	if len(result.Added)+len(result.Updated) == 0 {
+		return nil
+	}
+
+	if err := raw.setPlayers(*team, merged); err != nil {
+		return err
+	}
+
+	return raw.write(*configFile)
+}
+
+// encodePlayers returns the players formatted to paste into a config file.
+func encodePlayers(players []Player, format string) ([]byte, error) {
+	b, err := json.MarshalIndent(players, "", "  ")
+	if err != nil || format == configFormatJSON {
+		return b, err
+	}
+
+	// yaml and toml keys match the json field names.
+	var fields []any
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, fmt.Errorf("failed to convert players: %w", err)
+	}
+
+	var buf bytes.Buffer
+
+	if format == configFormatYAML {
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(2)
+
+		err = enc.Encode(map[string]any{"players": fields})
+	} else {
+		err = toml.NewEncoder(&buf).Encode(map[string]any{"players": fields})
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode players: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// rawConfig is a config file decoded only as far as the players, so settings
+// are written back as they were read.
+type rawConfig map[string]json.RawMessage
+
+// readRawConfig reads the config file in format, returning an empty config
+// when it doesn't exist yet.
+func readRawConfig(path, format string) (rawConfig, error) {
+	raw := make(rawConfig)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return raw, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	if b, err = toJSON(b, format); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse json config: %w", err)
+	}
+
+	return raw, nil
+}
+
+// teams returns the raw config of each team.
+func (rc rawConfig) teams() ([]map[string]json.RawMessage, error) {
+	var teams []map[string]json.RawMessage
+
+	if v, ok := rc["teams"]; ok {
+		if err := json.Unmarshal(v, &teams); err != nil {
+			return nil, fmt.Errorf("failed to parse teams: %w", err)
+		}
+	}
+
+	return teams, nil
+}
+
+// team returns the index of the named team, or -1 for the top level players.
+// The name may be omitted when only one list of players is configured.
+func (rc rawConfig) team(name string) (int, error) {
+	teams, err := rc.teams()
+	if err != nil {
+		return 0, err
+	}
+
+	// top level players are the team named defaultTeamName.
+	var players []Player
+	if v, ok := rc["players"]; ok {
+		if err := json.Unmarshal(v, &players); err != nil {
+			return 0, fmt.Errorf("failed to parse players: %w", err)
+		}
+	}
+
+	if name == "" {
+		switch {
+		case len(teams) == 0:
+			return -1, nil
+		case len(teams) == 1 && len(players) == 0:
+			return 0, nil
+		default:
+			return 0, errors.New("more than one team is configured, choose one with -team")
+		}
+	}
+
+	for i, team := range teams {
+		var teamName string
+		if err := json.Unmarshal(team["name"], &teamName); err == nil && teamName == name {
+			return i, nil
+		}
+	}
+
+	var top Config
+	if v, ok := rc["teamName"]; ok {
+		if err := json.Unmarshal(v, &top.TeamName); err != nil {
+			return 0, fmt.Errorf("failed to parse teamName: %w", err)
+		}
+	}
+
+	topName := top.topTeamName()
+
+	if name == topName {
+		return -1, nil
+	}
+
+	return 0, fmt.Errorf("team not found: %q", name)
+}
+
+// players returns the players and positions of the named team.
+func (rc rawConfig) players(name string) ([]Player, []string, error) {
+	i, err := rc.team(name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fields := map[string]json.RawMessage(rc)
+
+	if i >= 0 {
+		teams, err := rc.teams()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fields = teams[i]
+	}
+
+	var (
+		players   []Player
+		positions []string
+	)
+
+	if v, ok := fields["players"]; ok {
+		if err := json.Unmarshal(v, &players); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse players: %w", err)
+		}
+	}
+
+	if v, ok := fields["positions"]; ok && i >= 0 {
+		if err := json.Unmarshal(v, &positions); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse positions: %w", err)
+		}
+	}
+
+	return players, positions, nil
+}
+
+// setPlayers replaces the players of the named team.
+func (rc rawConfig) setPlayers(name string, players []Player) error {
+	b, err := json.Marshal(players)
+	if err != nil {
+		return fmt.Errorf("failed to encode players: %w", err)
+	}
+
+	i, err := rc.team(name)
+	if err != nil {
+		return err
+	}
+
+	if i < 0 {
+		rc["players"] = b
+
+		return nil
+	}
+
+	teams, err := rc.teams()
+	if err != nil {
+		return err
+	}
+
+	teams[i]["players"] = b
+
+	if rc["teams"], err = json.Marshal(teams); err != nil {
+		return fmt.Errorf("failed to encode teams: %w", err)
+	}
+
+	return nil
+}
+
+// write replaces the config file with rc, keeping its permissions. The config
+// is validated first and written to a temporary file renamed over the original,
+// so a running server never reloads an invalid or partly written config.
+func (rc rawConfig) write(path string) error {
+	b, err := json.MarshalIndent(rc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	if _, err := loadConfig(bytes.NewReader(b), configFormatJSON); err != nil {
+		return fmt.Errorf("refusing to write config file: %w", err)
+	}
+
+	mode := os.FileMode(0o600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary config file: %w", err)
+	}
+
+	// best effort clean up, a successful rename leaves nothing to remove.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append(b, '\n')); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to sync config file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close config file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRunImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+		err    error
+	}{
+		{
+			name:   "merged",
+			config: `{"players": [{"name": "jane", "number": 1}]}`,
+			want: `{
+  "players": [
+    {
+      "name": "jane",
+      "number": 1
+    },
+    {
+      "name": "mary",
+      "number": 2
+    }
+  ]
+}
+`,
+		},
+		{
+			name:   "invalid config",
+			config: `{"server": {"port": 70000}, "players": [{"name": "jane", "number": 1}]}`,
+			want:   `{"server": {"port": 70000}, "players": [{"name": "jane", "number": 1}]}`,
+			err:    ErrOutOfRange,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.json")
+			if err := os.WriteFile(path, []byte(tc.config), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := runImport([]string{"-configFile", path, "-"}, strings.NewReader("name,number\nmary,2\n"), io.Discard, io.Discard)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("runImport() error = %v, want %v", err, tc.err)
+			}
+
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.want, string(b)); diff != "" {
+				t.Errorf("config file mismatch (-want +got):\n%s", diff)
+			}
+
+			// only the config file is left, without temporary files.
+			if entries, _ := os.ReadDir(filepath.Dir(path)); len(entries) != 1 {
+				t.Errorf("config directory has %d files, want 1", len(entries))
+			}
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"syscall"
+	"time"
+)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// watchConfig reloads the configuration when the config file changes, or on
+// SIGHUP, until the context is cancelled.
+func (app *App) watchConfig(ctx context.Context) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+
+	version := app.configVersion()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			app.logger.Info("received SIGHUP, reloading configuration")
+
+			version = app.configVersion()
+			_ = app.reload()
+		case <-ticker.C:
+			if v := app.configVersion(); v != version {
+				version = v
+				_ = app.reload()
+			}
+		}
+	}
+}
+
+// fileVersion identifies a version of the config file.
+type fileVersion struct {
+	modified time.Time
+	size     int64
+}
+
+// configVersion returns the version of the config file, zero when it doesn't
+// exist.
+func (app *App) configVersion() fileVersion {
+	info, err := os.Stat(app.configFile)
+	if err != nil {
+		return fileVersion{}
+	}
+
+	return fileVersion{modified: info.ModTime(), size: info.Size()}
+}
+
+// reload loads the configuration again, applying new and changed players to
+// the running teams without disturbing their games. Invalid configurations
+// are logged and rejected, as is a team's roster that conflicts with its
+// saved events. Other changes are logged as needing a restart.
+func (app *App) reload() error {
+	config, err := app.load()
+	if err != nil {
+		app.logger.Error("rejected configuration reload", "error", err)
+
+		return err
+	}
+
+	app.logLevel.Set(config.LogLevel)
+
+	if !reflect.DeepEqual(app.config.Server, config.Server) || !reflect.DeepEqual(app.config.Auth, config.Auth) {
+		app.logger.Warn("restart to apply server and auth configuration changes")
+	}
+
+	previous := make(map[string]TeamConfig)
+	for _, team := range app.config.AllTeams() {
+		previous[team.Name] = team
+	}
+
+	var errs []error
+
+	for _, team := range config.AllTeams() {
+		s := app.subber(team.Name)
+		if s == nil {
+			app.logger.Warn("restart to serve new team", "team", team.Name)
+
+			continue
+		}
+
+		if err := s.SetRoster(team.Players); err != nil {
+			app.logger.Error("rejected roster reload", "team", team.Name, "error", err)
+			errs = append(errs, fmt.Errorf("team %s: %w", team.Name, err))
+
+			// keep the roster the team is still running with.
+			config.setPlayers(team.Name, previous[team.Name].Players)
+
+			continue
+		}
+
+		before := previous[team.Name]
+		before.Players, team.Players = nil, nil
+
+		if !reflect.DeepEqual(before, team) {
+			app.logger.Warn("restart to apply team configuration changes", "team", team.Name)
+		}
+	}
+
+	app.config = config
+	app.logger.Info("reloaded configuration", "file", app.configFile)
+
+	return errors.Join(errs...)
+}
+
+// subber returns the subber of the named team, nil if there isn't one.
+func (app *App) subber(name string) *Subber {
+	for _, s := range app.subbers {
+		if s.Name() == name {
+			return s
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestApp_reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	write := func(config string) {
+		t.Helper()
+
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	write(`{"teams": [{"name": "tigers", "players": [{"name": "jane", "number": 1}]}]}`)
+
+	app, err := NewApp([]string{"gosubs", "-configFile", path, "-stateDir", ""}, strings.NewReader(""), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("NewApp() error: %v", err)
+	}
+
+	s := app.subber("tigers")
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	if err := s.PlayerSubOn("jane"); err != nil {
+		t.Fatalf("PlayerSubOn(jane) error: %v", err)
+	}
+
+	write(`{"teams": [{"name": "tigers", "players": [{"name": "jane", "number": 1}, {"name": "john", "number": 2}]}]}`)
+
+	if err := app.reload(); err != nil {
+		t.Fatalf("reload() error: %v", err)
+	}
+
+	names := func() []string {
+		var names []string
+		for _, p := range s.ListPlayers() {
+			names = append(names, p.Name)
+		}
+
+		return names
+	}
+
+	if got := strings.Join(names(), ","); got != "jane,john" {
+		t.Errorf("players = %s, want jane,john", got)
+	}
+
+	if !s.ListPlayers()[0].Playing {
+		t.Error("jane was subbed off by the reload")
+	}
+
+	// duplicate names are rejected, leaving the roster unchanged.
+	write(`{"teams": [{"name": "tigers", "players": [{"name": "mary"}, {"name": "mary"}]}]}`)
+
+	if err := app.reload(); err == nil {
+		t.Error("reload() of an invalid config succeeded")
+	}
+
+	if got := strings.Join(names(), ","); got != "jane,john" {
+		t.Errorf("players = %s after invalid reload, want jane,john", got)
+	}
+}
+
+func TestApp_reload_RosterConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+
+	write := func(config string) {
+		t.Helper()
+
+		if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+			t.Fatalf("failed to write config: %v", err)
+		}
+	}
+
+	write(`{"teams": [{"name": "lions", "players": [{"name": "bob", "number": 1}]}, {"name": "tigers", "players": [{"name": "jane", "number": 1}]}]}`)
+
+	app, err := NewApp([]string{"gosubs", "-configFile", path, "-stateDir", ""}, strings.NewReader(""), io.Discard, io.Discard)
+	if err != nil {
+		t.Fatalf("NewApp() error: %v", err)
+	}
+
+	if err := app.subber("tigers").AddPlayer("mary", 3); err != nil {
+		t.Fatalf("AddPlayer(mary) error: %v", err)
+	}
+
+	// john wears the number mary was added with, conflicting with the event log.
+	write(`{"teams": [{"name": "lions", "players": [{"name": "bob", "number": 1}, {"name": "sue", "number": 2}]}, {"name": "tigers", "players": [{"name": "jane", "number": 1}, {"name": "john", "number": 3}]}]}`)
+
+	if err := app.reload(); !errors.Is(err, ErrRosterConflict) {
+		t.Fatalf("reload() error = %v, want %v", err, ErrRosterConflict)
+	}
+
+	// only the roster that applied is kept.
+	want := []TeamConfig{
+		{Name: "lions", Players: []Player{{Name: "bob", Number: 1}, {Name: "sue", Number: 2}}},
+		{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}}},
+	}
+
+	if diff := cmp.Diff(want, app.config.AllTeams()); diff != "" {
+		t.Errorf("config teams mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,115 @@
+// live.js keeps the page up to date without polling.
+//
+// Elements with a `data-sse` attribute open a Server-Sent Events stream to that
+// URL. Each event is named after the id of an element on the page which is
+// replaced by the event's HTML. The stream is closed once the element leaves
+// the page.
+//
+// Elements with a `data-since` attribute display the time elapsed since that
+// RFC 3339 timestamp, plus `data-base` milliseconds, ticking every second.
+//
+// Elements with a `data-until` attribute count down to that RFC 3339
+// timestamp, gaining a `data-time-up` attribute for styling when time is up.
+// Those with `data-alert` also beep and vibrate when time is up.
+//
+// Buttons with a `data-print` attribute open the browser's print dialog.
+(function () {
+  "use strict";
+
+  // remaining milliseconds seen for each countdown, keyed by `data-until` as
+  // elements are replaced whenever the page updates.
+  const remaining = new Map();
+  let audio;
+
+  // browsers only allow sound after the user has interacted with the page.
+  document.addEventListener("click", function () {
+    if (!audio && window.AudioContext) {
+      audio = new AudioContext();
+    }
+  });
+
+  document.addEventListener("click", function (evt) {
+    if (evt.target.closest("[data-print]")) {
+      window.print();
+    }
+  });
+
+  function timeUp() {
+    if (navigator.vibrate) {
+      navigator.vibrate([500, 200, 500, 200, 500]);
+    }
+    if (!audio) {
+      return;
+    }
+    [0, 0.6, 1.2].forEach(function (offset) {
+      const osc = audio.createOscillator();
+      osc.frequency.value = 880;
+      osc.connect(audio.destination);
+      osc.start(audio.currentTime + offset);
+      osc.stop(audio.currentTime + offset + 0.4);
+    });
+  }
+
+  function connect(elt) {
+    const source = new EventSource(elt.getAttribute("data-sse"));
+    const swap = function (evt) {
+      if (!document.body.contains(elt)) {
+        source.close();
+        return;
+      }
+      htmx.swap("#" + evt.type, evt.data, { swapStyle: "outerHTML" });
+    };
+
+    elt.getAttribute("data-sse-swap").split(",").forEach(function (name) {
+      source.addEventListener(name.trim(), swap);
+    });
+  }
+
+  // formatDuration matches Go's time.Duration.String() rounded to seconds.
+  function formatDuration(ms) {
+    let secs = Math.max(0, Math.round(ms / 1000));
+    const h = Math.floor(secs / 3600);
+    const m = Math.floor((secs % 3600) / 60);
+    const s = secs % 60;
+    if (h > 0) {
+      return h + "h" + m + "m" + s + "s";
+    }
+    if (m > 0) {
+      return m + "m" + s + "s";
+    }
+    return s + "s";
+  }
+
+  function tick() {
+    document.querySelectorAll("[data-since]").forEach(function (elt) {
+      const since = Date.parse(elt.getAttribute("data-since"));
+      const base = parseInt(elt.getAttribute("data-base") || "0", 10);
+      elt.textContent = formatDuration(base + Date.now() - since);
+    });
+
+    document.querySelectorAll("[data-until]").forEach(function (elt) {
+      const key = elt.getAttribute("data-until");
+      const left = Date.parse(key) - Date.now();
+      elt.textContent = formatDuration(left);
+      elt.toggleAttribute("data-time-up", left <= 0);
+
+      // only alert when time runs out while watching, not when opening a
+      // page after it already has.
+      const before = remaining.get(key);
+      remaining.set(key, left);
+      if (left <= 0 && before > 0 && elt.hasAttribute("data-alert")) {
+        timeUp();
+      }
+    });
+  }
+
+  htmx.onLoad(function (content) {
+    const elts = content.querySelectorAll ? content.querySelectorAll("[data-sse]") : [];
+    elts.forEach(connect);
+    if (content.hasAttribute && content.hasAttribute("data-sse")) {
+      connect(content);
+    }
+  });
+
+  setInterval(tick, 1000);
+})();
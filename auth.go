@@ -0,0 +1,142 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	// pinCoachName is the name of a coach logged in with the shared PIN.
+	pinCoachName    = "coach"
+	sessionDuration = 30 * 24 * time.Hour
+)
+
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// session is a logged in coach.
+type session struct {
+	Coach   string
+	Expires time.Time
+}
+
+// authenticator checks coach credentials and keeps their sessions in memory,
+// so coaches log in again after a restart.
+type authenticator struct {
+	config AuthConfig
+
+	mu       sync.Mutex
+	sessions map[string]session
+}
+
+func newAuthenticator(cfg AuthConfig) *authenticator {
+	return &authenticator{
+		config:   cfg,
+		sessions: make(map[string]session),
+	}
+}
+
+// enabled returns true when credentials are configured. Everyone is treated as
+// a coach when authentication is disabled.
+func (a *authenticator) enabled() bool {
+	return a.config.PIN != "" || len(a.config.Coaches) > 0
+}
+
+// login checks the coach's name and password, or the shared PIN, returning
+// the token of a new session.
+func (a *authenticator) login(name, password string, now time.Time) (string, session, error) {
+	coach, ok := a.check(name, password)
+	if !ok {
+		return "", session{}, ErrInvalidCredentials
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return "", session{}, err
+	}
+
+	s := session{Coach: coach, Expires: now.Add(sessionDuration)}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// drop expired sessions so abandoned logins don't accumulate.
+	for t, existing := range a.sessions {
+		if !now.Before(existing.Expires) {
+			delete(a.sessions, t)
+		}
+	}
+
+	a.sessions[token] = s
+
+	return token, s, nil
+}
+
+// check returns the name of the coach the credentials belong to.
+func (a *authenticator) check(name, password string) (string, bool) {
+	if password == "" {
+		return "", false
+	}
+
+	for _, c := range a.config.Coaches {
+		if c.Name == name && equal(c.Password, password) {
+			return c.Name, true
+		}
+	}
+
+	if a.config.PIN != "" && equal(a.config.PIN, password) {
+		return pinCoachName, true
+	}
+
+	return "", false
+}
+
+// lookup returns the unexpired session for the token.
+func (a *authenticator) lookup(token string, now time.Time) (session, bool) {
+	if token == "" {
+		return session{}, false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s, ok := a.sessions[token]
+	if !ok {
+		return session{}, false
+	}
+
+	if !now.Before(s.Expires) {
+		delete(a.sessions, token)
+
+		return session{}, false
+	}
+
+	return s, true
+}
+
+// logout ends the session for the token.
+func (a *authenticator) logout(token string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.sessions, token)
+}
+
+// equal compares secrets in constant time.
+func equal(want, got string) bool {
+	return subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
+
+// randomToken returns an unguessable token for use in cookies.
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAuthenticator_Login(t *testing.T) {
+	a := newAuthenticator(AuthConfig{
+		PIN:     "1234",
+		Coaches: []CoachConfig{{Name: "jane", Password: "secret"}},
+	})
+
+	tests := []struct {
+		name      string
+		user      string
+		password  string
+		wantCoach string
+		wantErr   error
+	}{
+		{name: "coach password", user: "jane", password: "secret", wantCoach: "jane"},
+		{name: "pin", password: "1234", wantCoach: pinCoachName},
+		{name: "wrong password", user: "jane", password: "1234x", wantErr: ErrInvalidCredentials},
+		{name: "password of another coach", user: "john", password: "secret", wantErr: ErrInvalidCredentials},
+		{name: "empty password", user: "jane", wantErr: ErrInvalidCredentials},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token, s, err := a.login(tc.user, tc.password, time.Now())
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("login() error = %v, want %v", err, tc.wantErr)
+			}
+
+			if tc.wantErr != nil {
+				return
+			}
+
+			if s.Coach != tc.wantCoach {
+				t.Errorf("login() coach = %q, want %q", s.Coach, tc.wantCoach)
+			}
+
+			if got, ok := a.lookup(token, time.Now()); !ok || got != s {
+				t.Errorf("lookup() = %v, %t, want %v, true", got, ok, s)
+			}
+		})
+	}
+}
+
+func TestAuthenticator_Sessions(t *testing.T) {
+	a := newAuthenticator(AuthConfig{PIN: "1234"})
+	now := time.Now()
+
+	token, _, err := a.login("", "1234", now)
+	if err != nil {
+		t.Fatalf("login() error: %v", err)
+	}
+
+	if _, ok := a.lookup(token, now.Add(sessionDuration)); ok {
+		t.Error("lookup() found expired session")
+	}
+
+	token, _, err = a.login("", "1234", now)
+	if err != nil {
+		t.Fatalf("login() error: %v", err)
+	}
+
+	a.logout(token)
+
+	if _, ok := a.lookup(token, now); ok {
+		t.Error("lookup() found logged out session")
+	}
+
+	if _, ok := a.lookup("", now); ok {
+		t.Error("lookup() found session for empty token")
+	}
+}
+
+func TestLocalRedirect(t *testing.T) {
+	tests := map[string]string{
+		"":                     "/",
+		"/teams/tigers/":       "/teams/tigers/",
+		"/teams/tigers/?at=1":  "/teams/tigers/?at=1",
+		"https://example.com/": "/",
+		"//example.com/":       "/",
+		`/\example.com`:        "/",
+		"teams":                "/",
+		"javascript:alert(1)":  "/",
+		"/teams/a%20b/history": "/teams/a%20b/history",
+	}
+
+	for next, want := range tests {
+		if got := localRedirect(next); got != want {
+			t.Errorf("localRedirect(%q) = %q, want %q", next, got, want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// clockInterval is how often the game clock is checked for a period's time
+// being up.
+const clockInterval = time.Second
+
+// PeriodEnd returns when the current period's time is up, zero when the game
+// is not in progress or periods are untimed.
+func (f GameFormat) PeriodEnd(g Game) time.Time {
+	if f.PeriodLength <= 0 || g.State() != GameStateInProgress {
+		return time.Time{}
+	}
+
+	return g.CurrentPeriod().StartTime.Add(time.Duration(f.PeriodLength))
+}
+
+// BreakEnd returns when the break after the current period is over, zero when
+// the game is not paused or breaks are untimed.
+func (f GameFormat) BreakEnd(g Game) time.Time {
+	if f.BreakLength <= 0 || g.State() != GameStatePaused {
+		return time.Time{}
+	}
+
+	return g.CurrentPeriod().EndTime.Add(time.Duration(f.BreakLength))
+}
+
+// Format returns the team's game format.
+func (s *Subber) Format() GameFormat {
+	return s.format
+}
+
+// RunClock pauses the game when a period's time is up, when the game format
+// enables AutoPause, until the context is cancelled.
+func (s *Subber) RunClock(ctx context.Context) error {
+	if !s.format.AutoPause || s.format.PeriodLength <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(clockInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			s.checkClock(now)
+		}
+	}
+}
+
+// checkClock pauses the game if the current period's time is up, returning
+// true if it did. Each period is only paused once, so a coach can undo the
+// pause to play on.
+func (s *Subber) checkClock(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.format.AutoPause {
+		return false
+	}
+
+	end := s.format.PeriodEnd(s.tally.game)
+	if end.IsZero() || now.Before(end) {
+		return false
+	}
+
+	if s.autoPaused(s.tally.game.CurrentPeriod().StartTime) {
+		return false
+	}
+
+	// pause when time was up, unless something happened after that.
+	at := end
+	if n := len(s.events); n > 0 && s.events[n-1].Time.After(at) {
+		at = s.events[n-1].Time
+	}
+
+	if err := s.record(Event{Time: at, Type: EventGamePaused, Auto: true}); err != nil {
+		s.logger.Error("failed to pause game at end of period", "error", err)
+
+		return false
+	}
+
+	s.logger.Info("paused game at end of period", "period", len(s.tally.game.periods))
+
+	return true
+}
+
+// autoPaused returns true if the clock has paused the period starting at
+// start, even if the pause was since undone. The event log is checked so a
+// restart doesn't pause the period again. Callers must hold s.mu.
+func (s *Subber) autoPaused(start time.Time) bool {
+	for _, e := range s.events {
+		if e.Type == EventGamePaused && e.Auto && !e.Time.Before(start) {
+			return true
+		}
+	}
+
+	return false
+}
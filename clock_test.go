@@ -0,0 +1,80 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestSubber_checkClock(t *testing.T) {
+	team := TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane"}, {Name: "john"}},
+		Format: GameFormat{
+			Periods:      4,
+			PeriodLength: Duration(12 * time.Minute),
+			AutoPause:    true,
+		},
+	}
+
+	s := newTestSubber(t, team)
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	if err := s.PlayerSubOn("jane"); err != nil {
+		t.Fatalf("PlayerSubOn(jane) error: %v", err)
+	}
+
+	start := s.CurrentGame().StartTime
+	end := start.Add(12 * time.Minute)
+
+	if got := s.Format().PeriodEnd(s.CurrentGame()); !got.Equal(end) {
+		t.Errorf("PeriodEnd() = %v, want %v", got, end)
+	}
+
+	if s.checkClock(end.Add(-time.Second)) {
+		t.Error("checkClock() paused before the period's time was up")
+	}
+
+	if !s.checkClock(end.Add(5 * time.Second)) {
+		t.Fatal("checkClock() did not pause when the period's time was up")
+	}
+
+	g := s.CurrentGame()
+	if g.State() != GameStatePaused {
+		t.Errorf("State() = %s, want %s", g.State(), GameStatePaused)
+	}
+
+	// paused when time was up, not when noticed.
+	if got := g.CurrentPeriod().EndTime; !got.Equal(end) {
+		t.Errorf("period EndTime = %v, want %v", got, end)
+	}
+
+	for _, p := range s.ListPlayers() {
+		if p.Name == "jane" && p.PlayDuration > 12*time.Minute {
+			t.Errorf("jane PlayDuration = %v, want at most 12m", p.PlayDuration)
+		}
+	}
+
+	// undoing the pause plays on.
+	if err := s.Undo(); err != nil {
+		t.Fatalf("Undo() error: %v", err)
+	}
+
+	if s.checkClock(end.Add(10 * time.Second)) {
+		t.Error("checkClock() paused again after the pause was undone")
+	}
+
+	// nor after a restart.
+	restarted, err := NewSubber(slog.New(slog.NewTextHandler(io.Discard, nil)), s.store, team)
+	if err != nil {
+		t.Fatalf("NewSubber() error: %v", err)
+	}
+
+	if restarted.checkClock(end.Add(15 * time.Second)) {
+		t.Error("checkClock() paused again after restarting")
+	}
+}
@@ -1,31 +1,633 @@
 package main
 
 import (
+	"bytes"
+	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTeamName is the name of the team created from the top level players
+// list, kept for configuration files written before teams were supported.
+const defaultTeamName = "default"
+
+// Config file formats, chosen by the file extension.
+const (
+	configFormatJSON = "json"
+	configFormatYAML = "yaml"
+	configFormatTOML = "toml"
+)
+
+var (
+	ErrUnknownConfigFormat = errors.New("unknown config file format, use .json, .yaml, .yml or .toml")
+	ErrUnknownField        = errors.New("unknown field")
+	ErrRequired            = errors.New("required")
+	ErrDuplicate           = errors.New("duplicate")
+	ErrOutOfRange          = errors.New("out of range")
 )
 
 // Config holds the configuration for an App.
 type Config struct {
+	// LogLevel is the minimum level logged, such as `debug` or `warn`.
+	LogLevel slog.Level   `json:"logLevel"`
+	Server   ServerConfig `json:"server"`
+	Auth     AuthConfig   `json:"auth"`
+	// Players of a single team, prefer Teams.
+	Players []Player `json:"players"`
+	// TeamName is the name of the team of the top level players, defaults to
+	// defaultTeamName.
+	TeamName string       `json:"teamName"`
+	Teams    []TeamConfig `json:"teams"`
+}
+
+// ServerConfig holds the configuration for the WebServer.
+type ServerConfig struct {
+	// Address to listen on, empty for all addresses.
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	// TLSCertFile and TLSKeyFile are paths to PEM encoded files, enabling HTTPS.
+	TLSCertFile string `json:"tlsCertFile"`
+	TLSKeyFile  string `json:"tlsKeyFile"`
+	// TLSSelfSigned enables HTTPS with a generated self-signed certificate when
+	// no certificate files are provided, for use on a home network.
+	TLSSelfSigned bool `json:"tlsSelfSigned"`
+	// AllowedOrigins may make cross-origin requests, such as a scoreboard
+	// hosted elsewhere, e.g. `https://scores.example.com`.
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+// AuthConfig holds the credentials coaches log in with to change subs. Anyone
+// can change subs when no credentials are configured.
+type AuthConfig struct {
+	// PIN shared by all coaches.
+	PIN string `json:"pin"`
+	// Coaches with their own passwords.
+	Coaches []CoachConfig `json:"coaches"`
+}
+
+// CoachConfig holds the credentials of a coach.
+type CoachConfig struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// TeamConfig holds the configuration for a team.
+type TeamConfig struct {
+	// Name of the team, expected to be unique.
+	Name    string   `json:"name"`
 	Players []Player `json:"players"`
+	// OnField is the number of players on the field at once, zero if unknown.
+	// Defaults to the number of Positions.
+	OnField int `json:"onField"`
+	// Positions on the field, such as `GK` or `C`, filled when subbing on.
+	Positions []string   `json:"positions"`
+	Format    GameFormat `json:"format"`
+	// SubInterval is the time between rolling subs, zero for no reminders.
+	SubInterval Duration `json:"subInterval"`
+}
+
+// GameFormat describes the periods of a game, such as 4 quarters of 12
+// minutes. Zero values are unknown, leaving periods untimed.
+type GameFormat struct {
+	Periods      int      `json:"periods"`
+	PeriodLength Duration `json:"periodLength"`
+	BreakLength  Duration `json:"breakLength"`
+	// AutoPause pauses the game when a period's time is up.
+	AutoPause bool `json:"autoPause"`
+}
+
+// Duration is a time.Duration written in configuration files in Go duration
+// format, e.g. `12m` or `1m30s`.
+type Duration time.Duration
+
+// UnmarshalText parses a duration such as `12m`.
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(v)
+
+	return nil
+}
+
+// MarshalText formats the duration such as `12m0s`.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
 }
 
 // DefaultConfiguration returns the default configuration values.
 func DefaultConfiguration() Config {
 	return Config{
+		Server: ServerConfig{
+			Port: defaultHTTPPort,
+		},
 		Players: make([]Player, 0),
+		Teams:   make([]TeamConfig, 0),
+	}
+}
+
+// AllTeams returns the configured teams, including a team named
+// defaultTeamName when top level players are configured.
+func (c Config) AllTeams() []TeamConfig {
+	teams := make([]TeamConfig, 0, len(c.Teams)+1)
+
+	if len(c.Players) > 0 {
+		teams = append(teams, TeamConfig{Name: c.topTeamName(), Players: c.Players})
+	}
+
+	return append(teams, c.Teams...)
+}
+
+// setPlayers replaces the players of the named team.
+func (c *Config) setPlayers(team string, players []Player) {
+	if len(c.Players) > 0 && c.topTeamName() == team {
+		c.Players = players
+
+		return
+	}
+
+	for i := range c.Teams {
+		if c.Teams[i].Name == team {
+			c.Teams[i].Players = players
+		}
+	}
+}
+
+// topTeamName returns the name of the team of the top level players.
+func (c Config) topTeamName() string {
+	if c.TeamName != "" {
+		return c.TeamName
+	}
+
+	return defaultTeamName
+}
+
+// envPrefix starts the name of every environment variable read by gosubs,
+// such as GOSUBS_PORT.
+const envPrefix = "GOSUBS_"
+
+// configEnv are the environment variables overriding scalar settings of the
+// config file, named without envPrefix.
+var configEnv = []struct {
+	name string
+	set  func(c *Config, v string) error
+}{
+	{"LOG_LEVEL", func(c *Config, v string) error {
+		return c.LogLevel.UnmarshalText([]byte(v))
+	}},
+	{"ADDRESS", func(c *Config, v string) error {
+		c.Server.Address = v
+		return nil
+	}},
+	{"PORT", func(c *Config, v string) (err error) {
+		c.Server.Port, err = strconv.Atoi(v)
+		return err
+	}},
+	{"TLS_CERT_FILE", func(c *Config, v string) error {
+		c.Server.TLSCertFile = v
+		return nil
+	}},
+	{"TLS_KEY_FILE", func(c *Config, v string) error {
+		c.Server.TLSKeyFile = v
+		return nil
+	}},
+	{"TLS_SELF_SIGNED", func(c *Config, v string) (err error) {
+		c.Server.TLSSelfSigned, err = strconv.ParseBool(v)
+		return err
+	}},
+	{"AUTH_PIN", func(c *Config, v string) error {
+		c.Auth.PIN = v
+		return nil
+	}},
+	{"TEAM_NAME", func(c *Config, v string) error {
+		c.TeamName = v
+		return nil
+	}},
+}
+
+// applyEnv overrides settings with the configEnv variables that are set,
+// returning a ValidationError listing any that can't be parsed.
+func (c *Config) applyEnv(lookup func(string) (string, bool)) error {
+	var ve ValidationError
+
+	for _, env := range configEnv {
+		v, ok := lookup(envPrefix + env.name)
+		if !ok {
+			continue
+		}
+
+		if err := env.set(c, v); err != nil {
+			ve.add(envPrefix+env.name, err)
+		}
+	}
+
+	if len(ve.Problems) > 0 {
+		return &ve
+	}
+
+	return nil
+}
+
+// envOr returns the value of the environment variable named without
+// envPrefix, or fallback when it isn't set.
+func envOr(lookup func(string) (string, bool), name, fallback string) string {
+	if v, ok := lookup(envPrefix + name); ok {
+		return v
+	}
+
+	return fallback
+}
+
+// configFormat returns the format of the config file from its extension.
+func configFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return configFormatJSON, nil
+	case ".yaml", ".yml":
+		return configFormatYAML, nil
+	case ".toml":
+		return configFormatTOML, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownConfigFormat, path)
 	}
 }
 
-// loadConfig reads the provided configuration file or input, validates and
-// returns a configuration object ready for use by the App.
-func loadConfig(input io.Reader) (Config, error) {
+// loadConfigFile reads the configuration file in the format of its
+// extension, see loadConfig.
+func loadConfigFile(path string) (Config, error) {
+	format, err := configFormat(path)
+	if err != nil {
+		return Config{}, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+	defer f.Close()
+
+	return loadConfig(f, format)
+}
+
+// toJSON converts a YAML or TOML config to JSON, so every format is decoded
+// and checked for unknown fields the same way.
+func toJSON(b []byte, format string) ([]byte, error) {
+	var fields any
+
+	switch format {
+	case configFormatJSON:
+		return b, nil
+	case configFormatYAML:
+		if err := yaml.Unmarshal(b, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse yaml config: %w", err)
+		}
+	case configFormatTOML:
+		if err := toml.Unmarshal(b, &fields); err != nil {
+			return nil, fmt.Errorf("failed to parse toml config: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownConfigFormat, format)
+	}
+
+	// an empty yaml file is null.
+	if fields == nil {
+		fields = map[string]any{}
+	}
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s config: %w", format, err)
+	}
+
+	return b, nil
+}
+
+// loadConfig reads the provided configuration file or input in format,
+// validates and returns a configuration object ready for use by the App.
+// Unknown fields and invalid values are returned together as a
+// ValidationError.
+func loadConfig(input io.Reader, format string) (Config, error) {
+	b, err := io.ReadAll(input)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if b, err = toJSON(b, format); err != nil {
+		return Config{}, err
+	}
+
 	cfg := DefaultConfiguration()
 
-	if err := json.NewDecoder(input).Decode(&cfg); err != nil {
+	if err := json.Unmarshal(b, &cfg); err != nil {
 		return Config{}, fmt.Errorf("failed to parse json config: %w", err)
 	}
 
+	var fields any
+	if err := json.NewDecoder(bytes.NewReader(b)).Decode(&fields); err != nil {
+		return Config{}, fmt.Errorf("failed to parse json config: %w", err)
+	}
+
+	var ve ValidationError
+
+	unknownFields(&ve, "", fields, reflect.TypeOf(cfg))
+
+	if err := cfg.Validate(); err != nil {
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) {
+			return Config{}, err
+		}
+
+		ve.Problems = append(ve.Problems, invalid.Problems...)
+	}
+
+	if len(ve.Problems) > 0 {
+		return Config{}, &ve
+	}
+
 	return cfg, nil
 }
+
+// FieldError is a problem with the configuration value at a JSON path, such
+// as `teams[0].players[2].number`.
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (fe FieldError) Error() string {
+	return fe.Path + ": " + fe.Err.Error()
+}
+
+func (fe FieldError) Unwrap() error {
+	return fe.Err
+}
+
+// ValidationError lists every problem found in a configuration, so they can
+// all be fixed at once.
+type ValidationError struct {
+	Problems []FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	problems := make([]string, 0, len(ve.Problems))
+	for _, p := range ve.Problems {
+		problems = append(problems, p.Error())
+	}
+
+	return "invalid config:\n  " + strings.Join(problems, "\n  ")
+}
+
+func (ve *ValidationError) Unwrap() []error {
+	errs := make([]error, 0, len(ve.Problems))
+	for _, p := range ve.Problems {
+		errs = append(errs, p)
+	}
+
+	return errs
+}
+
+// add records a problem with the value at path.
+func (ve *ValidationError) add(path string, err error) {
+	ve.Problems = append(ve.Problems, FieldError{Path: path, Err: err})
+}
+
+// Validate returns a ValidationError listing every missing, duplicate or out
+// of range value.
+func (c Config) Validate() error {
+	var ve ValidationError
+
+	if c.Server.Port < 0 || c.Server.Port > 65535 {
+		ve.add("server.port", fmt.Errorf("%w: %d, must be 0 to 65535", ErrOutOfRange, c.Server.Port))
+	}
+
+	if (c.Server.TLSCertFile == "") != (c.Server.TLSKeyFile == "") {
+		path := "server.tlsKeyFile"
+		if c.Server.TLSCertFile == "" {
+			path = "server.tlsCertFile"
+		}
+
+		ve.add(path, fmt.Errorf("%w, set both tlsCertFile and tlsKeyFile", ErrRequired))
+	}
+
+	for i, origin := range c.Server.AllowedOrigins {
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") {
+			ve.add(fmt.Sprintf("server.allowedOrigins[%d]", i),
+				fmt.Errorf("invalid origin %q, such as https://scores.example.com", origin))
+		}
+	}
+
+	coaches := make(map[string]int)
+
+	for i, coach := range c.Auth.Coaches {
+		path := fmt.Sprintf("auth.coaches[%d]", i)
+
+		if coach.Name == "" {
+			ve.add(path+".name", ErrRequired)
+		} else if first, ok := coaches[coach.Name]; ok {
+			ve.add(path+".name", fmt.Errorf("%w: %q, also auth.coaches[%d]", ErrDuplicate, coach.Name, first))
+		} else {
+			coaches[coach.Name] = i
+		}
+
+		if coach.Password == "" {
+			ve.add(path+".password", ErrRequired)
+		}
+	}
+
+	validatePlayers(&ve, "players", c.Players, nil)
+
+	if strings.Contains(c.TeamName, "/") {
+		ve.add("teamName", fmt.Errorf("invalid team name %q, must not contain '/'", c.TeamName))
+	}
+
+	teams := make(map[string]string)
+	if len(c.Players) > 0 {
+		teams[c.topTeamName()] = "players"
+	}
+
+	for i, team := range c.Teams {
+		path := fmt.Sprintf("teams[%d]", i)
+
+		switch {
+		case team.Name == "":
+			ve.add(path+".name", ErrRequired)
+		case strings.Contains(team.Name, "/"):
+			ve.add(path+".name", fmt.Errorf("invalid team name %q, must not contain '/'", team.Name))
+		case teams[team.Name] != "":
+			ve.add(path+".name", fmt.Errorf("%w: %q, also %s", ErrDuplicate, team.Name, teams[team.Name]))
+		default:
+			teams[team.Name] = path
+		}
+
+		team.validate(&ve, path)
+	}
+
+	if len(ve.Problems) > 0 {
+		return &ve
+	}
+
+	return nil
+}
+
+// validate adds the team's problems to ve.
+func (tc TeamConfig) validate(ve *ValidationError, path string) {
+	if tc.OnField < 0 {
+		ve.add(path+".onField", fmt.Errorf("%w: %d, must not be negative", ErrOutOfRange, tc.OnField))
+	}
+
+	for i, pos := range tc.Positions {
+		switch {
+		case strings.TrimSpace(pos) == "":
+			ve.add(fmt.Sprintf("%s.positions[%d]", path, i), ErrRequired)
+		case slices.Index(tc.Positions, pos) < i:
+			ve.add(fmt.Sprintf("%s.positions[%d]", path, i), fmt.Errorf("%w: %q", ErrDuplicate, pos))
+		}
+	}
+
+	durations := []struct {
+		name  string
+		value Duration
+	}{
+		{"format.periodLength", tc.Format.PeriodLength},
+		{"format.breakLength", tc.Format.BreakLength},
+		{"subInterval", tc.SubInterval},
+	}
+
+	for _, d := range durations {
+		if d.value < 0 {
+			ve.add(path+"."+d.name, fmt.Errorf("%w: %s, must not be negative", ErrOutOfRange, time.Duration(d.value)))
+		}
+	}
+
+	if tc.Format.Periods < 0 {
+		ve.add(path+".format.periods", fmt.Errorf("%w: %d, must not be negative", ErrOutOfRange, tc.Format.Periods))
+	}
+
+	validatePlayers(ve, path+".players", tc.Players, tc.Positions)
+}
+
+// validatePlayers adds problems with the players of a team to ve, checking
+// preferred positions when positions are known.
+func validatePlayers(ve *ValidationError, path string, players []Player, positions []string) {
+	names := make(map[string]int)
+	numbers := make(map[int]int)
+
+	for i, p := range players {
+		at := fmt.Sprintf("%s[%d]", path, i)
+
+		if p.Name == "" || strings.Contains(p.Name, "/") {
+			ve.add(at+".name", fmt.Errorf("%w: %q", ErrInvalidPlayerName, p.Name))
+		} else if first, ok := names[p.Name]; ok {
+			ve.add(at+".name", fmt.Errorf("%w: %q, also %s[%d]", ErrDuplicate, p.Name, path, first))
+		} else {
+			names[p.Name] = i
+		}
+
+		if p.Number < 0 {
+			ve.add(at+".number", fmt.Errorf("%w: %d, must not be negative", ErrOutOfRange, p.Number))
+		} else if first, ok := numbers[p.Number]; ok && p.Number != 0 {
+			ve.add(at+".number", fmt.Errorf("%w: %d, also %s[%d]", ErrDuplicate, p.Number, path, first))
+		} else {
+			numbers[p.Number] = i
+		}
+
+		if p.PreferredPosition != "" && len(positions) > 0 && !slices.Contains(positions, p.PreferredPosition) {
+			ve.add(at+".position", fmt.Errorf("%w: %q, must be one of %s",
+				ErrInvalidPosition, p.PreferredPosition, strings.Join(positions, ", ")))
+		}
+
+		if p.Availability != "" && !slices.Contains(Availabilities, p.Availability) {
+			ve.add(at+".availability", fmt.Errorf("%w: %q", ErrInvalidAvailability, p.Availability))
+		}
+	}
+}
+
+// unknownFields adds a problem to ve for each field of the decoded JSON value
+// that t has no field for, matching names ignoring case as encoding/json does.
+func unknownFields(ve *ValidationError, path string, value any, t reflect.Type) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		return
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Map:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				unknownFields(ve, joinPath(path, key), v[key], t.Elem())
+			}
+		case reflect.Struct:
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				f, ok := jsonField(t, key)
+				if !ok {
+					ve.add(joinPath(path, key), ErrUnknownField)
+
+					continue
+				}
+
+				unknownFields(ve, joinPath(path, key), v[key], f.Type)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, item := range v {
+				unknownFields(ve, fmt.Sprintf("%s[%d]", path, i), item, t.Elem())
+			}
+		}
+	}
+}
+
+// jsonField returns the struct field decoded from the JSON key.
+func jsonField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+
+		if strings.EqualFold(name, key) {
+			return f, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// joinPath returns the JSON path of key within path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
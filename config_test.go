@@ -2,8 +2,11 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"log/slog"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 )
@@ -13,7 +16,7 @@ func TestLoadConfig_EmptyConfig(t *testing.T) {
 
 	buf := bytes.NewBufferString("{}")
 
-	got, err := loadConfig(buf)
+	got, err := loadConfig(buf, configFormatJSON)
 	if err != nil {
 		t.Errorf("failed to load empty config: %v", err)
 	}
@@ -35,7 +38,7 @@ func TestLoadConfig_OverrideField(t *testing.T) {
 
 	buf := bytes.NewBufferString(json)
 
-	got, err := loadConfig(buf)
+	got, err := loadConfig(buf, configFormatJSON)
 	if err != nil {
 		t.Errorf("failed to load empty config: %v", err)
 	}
@@ -47,12 +50,19 @@ func TestLoadConfig_OverrideField(t *testing.T) {
 
 func TestLoadConfig_ConfigJsonFile(t *testing.T) {
 	want := Config{
-		Players: []Player{
-			{Name: "jane", Number: 1},
-			{Name: "john", Number: 2},
-			{Name: "steve", Number: 3},
-			{Name: "mary", Number: 4},
-			{Name: "bob", Number: 5},
+		Server:  ServerConfig{Port: defaultHTTPPort},
+		Players: []Player{},
+		Teams: []TeamConfig{
+			{
+				Name: "tigers",
+				Players: []Player{
+					{Name: "jane", Number: 1},
+					{Name: "john", Number: 2},
+					{Name: "steve", Number: 3},
+					{Name: "mary", Number: 4},
+					{Name: "bob", Number: 5},
+				},
+			},
 		},
 	}
 
@@ -63,7 +73,7 @@ func TestLoadConfig_ConfigJsonFile(t *testing.T) {
 
 	buf := bytes.NewBuffer(json)
 
-	got, err := loadConfig(buf)
+	got, err := loadConfig(buf, configFormatJSON)
 	if err != nil {
 		t.Errorf("failed to load empty config: %v", err)
 	}
@@ -74,3 +84,290 @@ func TestLoadConfig_ConfigJsonFile(t *testing.T) {
 		t.Errorf("loadConfig(...) mismatch (-want +got):\n%s", diff)
 	}
 }
+
+func TestConfig_AllTeams(t *testing.T) {
+	cfg := DefaultConfiguration()
+	cfg.Players = []Player{{Name: "kunio", Number: 86}}
+	cfg.Teams = []TeamConfig{{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}}}}
+
+	want := []TeamConfig{
+		{Name: defaultTeamName, Players: []Player{{Name: "kunio", Number: 86}}},
+		{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}}},
+	}
+
+	if diff := cmp.Diff(want, cfg.AllTeams()); diff != "" {
+		t.Errorf("AllTeams() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLoadConfig_Invalid(t *testing.T) {
+	json := `
+	{
+	"server": {"port": 70000, "tlsCertFile": "cert.pem", "allowedOrigins": ["scores.example.com"]},
+	"auth": {"coaches": [{"name": "sam", "password": "x"}, {"name": "sam"}]},
+	"players": [{"name": "kunio", "number": 86, "age": 9}],
+	"teams": [
+		{
+			"name": "tigers",
+			"positions": ["GK", "C", "GK"],
+			"players": [
+				{"name": "jane", "number": 1, "position": "WD"},
+				{"name": "jane", "number": 1},
+				{"name": "", "number": -2, "availability": "away"}
+			],
+			"format": {"periods": 4, "periodLength": "-12m", "autopause": true},
+			"subinterval": "5m"
+		},
+		{"name": "tigers", "colour": "orange"}
+	]
+	}
+	`
+
+	want := []string{
+		"players[0].age: unknown field",
+		"teams[1].colour: unknown field",
+		"server.port: out of range: 70000, must be 0 to 65535",
+		"server.tlsKeyFile: required, set both tlsCertFile and tlsKeyFile",
+		`server.allowedOrigins[0]: invalid origin "scores.example.com", such as https://scores.example.com`,
+		`auth.coaches[1].name: duplicate: "sam", also auth.coaches[0]`,
+		"auth.coaches[1].password: required",
+		`teams[0].positions[2]: duplicate: "GK"`,
+		"teams[0].format.periodLength: out of range: -12m0s, must not be negative",
+		`teams[0].players[0].position: invalid position: "WD", must be one of GK, C, GK`,
+		`teams[0].players[1].name: duplicate: "jane", also teams[0].players[0]`,
+		"teams[0].players[1].number: duplicate: 1, also teams[0].players[0]",
+		`teams[0].players[2].name: player name must not be empty or contain '/': ""`,
+		"teams[0].players[2].number: out of range: -2, must not be negative",
+		`teams[0].players[2].availability: invalid availability: "away"`,
+		`teams[1].name: duplicate: "tigers", also teams[0]`,
+	}
+
+	_, err := loadConfig(bytes.NewBufferString(json), configFormatJSON)
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("loadConfig() error = %v, want a ValidationError", err)
+	}
+
+	got := make([]string, 0, len(ve.Problems))
+	for _, p := range ve.Problems {
+		got = append(got, p.Error())
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("loadConfig() problems mismatch (-want +got):\n%s", diff)
+	}
+
+	if !errors.Is(err, ErrDuplicate) || !errors.Is(err, ErrUnknownField) {
+		t.Errorf("loadConfig() error = %v, want it to wrap ErrDuplicate and ErrUnknownField", err)
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		err  error
+	}{
+		{
+			name: "default",
+			cfg:  DefaultConfiguration(),
+		},
+		{
+			name: "valid team",
+			cfg: Config{
+				Server: ServerConfig{Port: 443, AllowedOrigins: []string{"https://scores.example.com"}},
+				Teams: []TeamConfig{{
+					Name:      "tigers",
+					Positions: []string{"GK", "C"},
+					Players: []Player{
+						{Name: "jane", Number: 1, PreferredPosition: "GK"},
+						{Name: "john", Availability: AvailabilityAbsent},
+						{Name: "mary"},
+					},
+				}},
+			},
+		},
+		{
+			name: "team named after top level players",
+			cfg: Config{
+				Players: []Player{{Name: "jane"}},
+				Teams:   []TeamConfig{{Name: defaultTeamName}},
+			},
+			err: ErrDuplicate,
+		},
+		{
+			name: "team without name",
+			cfg:  Config{Teams: []TeamConfig{{}}},
+			err:  ErrRequired,
+		},
+		{
+			name: "negative on field",
+			cfg:  Config{Teams: []TeamConfig{{Name: "tigers", OnField: -1}}},
+			err:  ErrOutOfRange,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.err == nil && err != nil {
+				t.Fatalf("Validate() error = %v, want nil", err)
+			}
+
+			if !errors.Is(err, tc.err) {
+				t.Errorf("Validate() error = %v, want %v", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestLoadConfig_Formats(t *testing.T) {
+	want := DefaultConfiguration()
+	want.LogLevel = slog.LevelDebug
+	want.Server.Port = 8443
+	want.Teams = []TeamConfig{
+		{
+			Name:      "tigers",
+			Positions: []string{"GK", "C"},
+			Players:   []Player{{Name: "jane", Number: 1, PreferredPosition: "GK"}, {Name: "john", Number: 2}},
+			Format:    GameFormat{Periods: 4, PeriodLength: Duration(12 * time.Minute)},
+		},
+	}
+
+	tests := []struct {
+		format string
+		config string
+	}{
+		{
+			format: configFormatJSON,
+			config: `{
+				"logLevel": "debug",
+				"server": {"port": 8443},
+				"teams": [{
+					"name": "tigers",
+					"positions": ["GK", "C"],
+					"players": [{"name": "jane", "number": 1, "position": "GK"}, {"name": "john", "number": 2}],
+					"format": {"periods": 4, "periodLength": "12m"}
+				}]
+			}`,
+		},
+		{
+			format: configFormatYAML,
+			config: `
+logLevel: debug
+server:
+  port: 8443
+teams:
+  - name: tigers
+    positions: [GK, C]
+    players:
+      - {name: jane, number: 1, position: GK}
+      - {name: john, number: 2}
+    format:
+      periods: 4
+      periodLength: 12m
+`,
+		},
+		{
+			format: configFormatTOML,
+			config: `
+logLevel = "debug"
+
+[server]
+port = 8443
+
+[[teams]]
+name = "tigers"
+positions = ["GK", "C"]
+format = { periods = 4, periodLength = "12m" }
+
+[[teams.players]]
+name = "jane"
+number = 1
+position = "GK"
+
+[[teams.players]]
+name = "john"
+number = 2
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := loadConfig(bytes.NewBufferString(tc.config), tc.format)
+			if err != nil {
+				t.Fatalf("loadConfig() error: %v", err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("loadConfig(...) mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	// unknown fields are found in every format.
+	_, err := loadConfig(bytes.NewBufferString("server:\n  prot: 8443\n"), configFormatYAML)
+	if !errors.Is(err, ErrUnknownField) {
+		t.Errorf("loadConfig() error = %v, want %v", err, ErrUnknownField)
+	}
+}
+
+func TestConfigFormat(t *testing.T) {
+	for path, want := range map[string]string{
+		"config.json":      configFormatJSON,
+		"/etc/gosubs.yaml": configFormatYAML,
+		"gosubs.YML":       configFormatYAML,
+		"gosubs.toml":      configFormatTOML,
+	} {
+		if got, err := configFormat(path); err != nil || got != want {
+			t.Errorf("configFormat(%q) = %q, %v, want %q", path, got, err, want)
+		}
+	}
+
+	if _, err := configFormat("config.ini"); !errors.Is(err, ErrUnknownConfigFormat) {
+		t.Errorf("configFormat(config.ini) error = %v, want %v", err, ErrUnknownConfigFormat)
+	}
+}
+
+func TestConfig_applyEnv(t *testing.T) {
+	env := map[string]string{
+		"GOSUBS_PORT":            "8443",
+		"GOSUBS_LOG_LEVEL":       "warn",
+		"GOSUBS_TEAM_NAME":       "tigers",
+		"GOSUBS_TLS_SELF_SIGNED": "true",
+		"PORT":                   "9000",
+	}
+
+	cfg := DefaultConfiguration()
+	cfg.Server.Address = "127.0.0.1"
+
+	if err := cfg.applyEnv(lookup(env)); err != nil {
+		t.Fatalf("applyEnv() error: %v", err)
+	}
+
+	want := DefaultConfiguration()
+	want.LogLevel = slog.LevelWarn
+	want.Server = ServerConfig{Address: "127.0.0.1", Port: 8443, TLSSelfSigned: true}
+	want.TeamName = "tigers"
+
+	if diff := cmp.Diff(want, cfg); diff != "" {
+		t.Errorf("applyEnv() mismatch (-want +got):\n%s", diff)
+	}
+
+	err := cfg.applyEnv(lookup(map[string]string{"GOSUBS_PORT": "http", "GOSUBS_LOG_LEVEL": "loud"}))
+
+	var ve *ValidationError
+	if !errors.As(err, &ve) || len(ve.Problems) != 2 {
+		t.Errorf("applyEnv() error = %v, want problems with GOSUBS_LOG_LEVEL and GOSUBS_PORT", err)
+	}
+}
+
+// lookup returns a lookup of the environment variables in env.
+func lookup(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
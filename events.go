@@ -0,0 +1,841 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrPlayerNotFound      = errors.New("player not found")
+	ErrGameNotStarted      = errors.New("game not started")
+	ErrGameAlreadyStarted  = errors.New("game already started")
+	ErrGameNotInProgress   = errors.New("game not in progress")
+	ErrGameNotPaused       = errors.New("game not paused")
+	ErrGameFinished        = errors.New("game finished")
+	ErrFieldFull           = errors.New("field full, sub a player off first")
+	ErrInvalidSwap         = errors.New("invalid swap")
+	ErrInvalidScore        = errors.New("invalid score")
+	ErrInvalidPosition     = errors.New("invalid position")
+	ErrPositionFilled      = errors.New("position filled, sub a player off first")
+	ErrPlayerUnavailable   = errors.New("player unavailable")
+	ErrInvalidAvailability = errors.New("invalid availability")
+	ErrInvalidPlayerName   = errors.New("player name must not be empty or contain '/'")
+	ErrInvalidPlayerNumber = errors.New("invalid player number")
+	ErrPlayerExists        = errors.New("player already exists")
+	ErrNumberTaken         = errors.New("number already taken")
+	ErrPlayerOnField       = errors.New("player on field, sub them off first")
+	ErrRosterConflict      = errors.New("roster conflicts with saved events")
+	ErrNothingToUndo       = errors.New("nothing to undo")
+	ErrNothingToRedo       = errors.New("nothing to redo")
+)
+
+type EventType string
+
+const (
+	EventGameStarted  EventType = "game_started"
+	EventGamePaused   EventType = "game_paused"
+	EventGameResumed  EventType = "game_resumed"
+	EventGameEnded    EventType = "game_ended"
+	EventGameReset    EventType = "game_reset"
+	EventPlayerReset  EventType = "player_reset"
+	EventPlayerSet    EventType = "player_set"
+	EventPlayerSubOn  EventType = "player_sub_on"
+	EventPlayerSubOff EventType = "player_sub_off"
+	// EventPlayerAvailability marks a player available or not for this game.
+	EventPlayerAvailability EventType = "player_availability"
+	// EventPlayerAdded adds a player to the roster.
+	EventPlayerAdded EventType = "player_added"
+	// EventPlayerUpdated renames or renumbers a player, keeping their stats.
+	EventPlayerUpdated EventType = "player_updated"
+	// EventPlayerRemoved removes a player from the roster.
+	EventPlayerRemoved EventType = "player_removed"
+	// EventPlayersImported adds and updates players from an imported roster,
+	// undone as one action.
+	EventPlayersImported EventType = "players_imported"
+	// EventSwap subs players off and on at the same moment.
+	EventSwap EventType = "swap"
+	// EventScore adds points for the team, optionally crediting a scorer and an
+	// assist.
+	EventScore EventType = "score"
+	// EventOpponentScore adds points for the opposition.
+	EventOpponentScore EventType = "opponent_score"
+	// EventUndo reverts the most recent action that has not been undone.
+	EventUndo EventType = "undo"
+	// EventRedo restores the most recently undone action.
+	EventRedo EventType = "redo"
+)
+
+func (et EventType) String() string {
+	return string(et)
+}
+
+// Event records a single game or player action. Subber state is derived by
+// replaying events in order.
+type Event struct {
+	Time   time.Time `json:"time"`
+	Type   EventType `json:"type"`
+	Player string    `json:"player,omitempty"`
+	// Position is filled by the player of an EventPlayerSubOn, empty if unknown,
+	// or the preferred position of an EventPlayerAdded or EventPlayerUpdated.
+	Position string `json:"position,omitempty"`
+	// PlayCount and PlayDuration are only set for EventPlayerSet corrections.
+	PlayCount    int           `json:"playCount,omitempty"`
+	PlayDuration time.Duration `json:"playDuration,omitempty"`
+	// Off and On are the players of an EventSwap.
+	Off []string `json:"off,omitempty"`
+	On  []string `json:"on,omitempty"`
+	// Points of an EventScore or EventOpponentScore, where Player is the
+	// optional scorer, credited along with Assist.
+	Points int    `json:"points,omitempty"`
+	Assist string `json:"assist,omitempty"`
+	// Availability of the player of an EventPlayerAvailability or
+	// EventPlayerAdded.
+	Availability Availability `json:"availability,omitempty"`
+	// Number of the player of an EventPlayerAdded or EventPlayerUpdated, and
+	// NewName of an updated player, empty to keep their name.
+	Number  int    `json:"number,omitempty"`
+	NewName string `json:"newName,omitempty"`
+	// Auto is true for an EventGamePaused made by the clock when a period's
+	// time was up.
+	Auto bool `json:"auto,omitempty"`
+	// Players added or updated by an EventPlayersImported, with their roster
+	// details after the import.
+	Players []Player `json:"players,omitempty"`
+}
+
+// Description returns a short human readable summary of the event.
+func (e Event) Description() string {
+	switch e.Type {
+	case EventGameStarted:
+		return "start game"
+	case EventGamePaused:
+		if e.Auto {
+			return "pause game at end of period"
+		}
+
+		return "pause game"
+	case EventGameResumed:
+		return "resume game"
+	case EventGameEnded:
+		return "end game"
+	case EventGameReset:
+		return "reset game"
+	case EventPlayerReset:
+		return "reset " + e.Player
+	case EventPlayerSet:
+		return "set " + e.Player
+	case EventPlayerSubOn:
+		if e.Position != "" {
+			return fmt.Sprintf("sub on %s at %s", e.Player, e.Position)
+		}
+
+		return "sub on " + e.Player
+	case EventPlayerSubOff:
+		return "sub off " + e.Player
+	case EventPlayerAvailability:
+		return fmt.Sprintf("mark %s %s", e.Player, e.Availability)
+	case EventPlayerAdded:
+		return "add " + e.Player
+	case EventPlayerUpdated:
+		if e.NewName != "" && e.NewName != e.Player {
+			return fmt.Sprintf("rename %s to %s", e.Player, e.NewName)
+		}
+
+		return fmt.Sprintf("number %s %d", e.Player, e.Number)
+	case EventPlayerRemoved:
+		return "remove " + e.Player
+	case EventPlayersImported:
+		return fmt.Sprintf("import %d players", len(e.Players))
+	case EventSwap:
+		return fmt.Sprintf("swap %s for %s", strings.Join(e.Off, ", "), strings.Join(e.On, ", "))
+	case EventScore:
+		if e.Player != "" {
+			return fmt.Sprintf("score %d by %s", e.Points, e.Player)
+		}
+
+		return fmt.Sprintf("score %d", e.Points)
+	case EventOpponentScore:
+		return fmt.Sprintf("opponent score %d", e.Points)
+	default:
+		return e.Type.String()
+	}
+}
+
+// resolve walks the event log applying undo and redo events, returning the
+// actions still in effect and the undone actions available to redo, most
+// recent last.
+func resolve(events []Event) ([]Event, []Event) {
+	var applied, undone []Event
+
+	for _, e := range events {
+		switch e.Type {
+		case EventUndo:
+			if len(applied) == 0 {
+				continue
+			}
+
+			undone = append(undone, applied[len(applied)-1])
+			applied = applied[:len(applied)-1]
+
+		case EventRedo:
+			if len(undone) == 0 {
+				continue
+			}
+
+			applied = append(applied, undone[len(undone)-1])
+			undone = undone[:len(undone)-1]
+
+		default:
+			applied = append(applied, e)
+			// a new action replaces any undone history.
+			undone = nil
+		}
+	}
+
+	return applied, undone
+}
+
+// tally is the game and player statistics resulting from applying events to a
+// roster.
+type tally struct {
+	game    Game
+	players map[string]Player // map[name]Player
+	// history of finished games, oldest first.
+	history []GameRecord
+	// lastSub is when players were last subbed on or off.
+	lastSub time.Time
+	// stints played this game, in the order they started.
+	stints []Stint
+}
+
+// newTally returns a tally for roster with no game played.
+func newTally(roster []Player) *tally {
+	ps := make(map[string]Player)
+
+	for _, player := range roster {
+		ps[player.Name] = newPlayer(player)
+	}
+
+	return &tally{
+		game:    Game{},
+		players: ps,
+	}
+}
+
+// newPlayer returns a player with the roster details of p and no statistics.
+func newPlayer(p Player) Player {
+	availability := p.Availability
+	if availability == AvailabilityAvailable {
+		availability = ""
+	}
+
+	return Player{
+		Name:              p.Name,
+		Number:            p.Number,
+		PreferredPosition: p.PreferredPosition,
+		Availability:      availability,
+	}
+}
+
+// replay applies the actions in effect after undo and redo to a new tally for
+// roster. Events that can no longer be applied, for example a player since
+// removed from the roster, are returned as errors but do not stop the replay.
+func replay(roster []Player, events []Event) (*tally, []error) {
+	t := newTally(roster)
+
+	var errs []error
+
+	applied, _ := resolve(events)
+	for _, e := range applied {
+		if err := t.apply(e); err != nil {
+			errs = append(errs, fmt.Errorf("%s at %s: %w", e.Type, e.Time.Format(time.RFC3339), err))
+		}
+	}
+
+	return t, errs
+}
+
+// checkSwap returns an error unless the swap subs off players who are playing
+// for available players who aren't, each named once, during a game.
+func (t *tally) checkSwap(e Event) error {
+	if t.game.State() == GameStateNotStarted {
+		return fmt.Errorf("%w: %w", ErrInvalidSwap, ErrGameNotStarted)
+	}
+
+	if len(e.Off) == 0 && len(e.On) == 0 {
+		return fmt.Errorf("%w: no players provided", ErrInvalidSwap)
+	}
+
+	seen := make(map[string]bool, len(e.Off)+len(e.On))
+
+	for _, name := range e.Off {
+		p, ok := t.players[name]
+
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+		case seen[name]:
+			return fmt.Errorf("%w: %s subbed off twice", ErrInvalidSwap, name)
+		case !p.Playing:
+			return fmt.Errorf("%w: %s not playing", ErrInvalidSwap, name)
+		}
+
+		seen[name] = true
+	}
+
+	for _, name := range e.On {
+		p, ok := t.players[name]
+
+		switch {
+		case !ok:
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+		case slices.Contains(e.Off, name):
+			return fmt.Errorf("%w: %s subbed both off and on", ErrInvalidSwap, name)
+		case seen[name]:
+			return fmt.Errorf("%w: %s subbed on twice", ErrInvalidSwap, name)
+		case p.Playing:
+			return fmt.Errorf("%w: %s already playing", ErrInvalidSwap, name)
+		case !p.Available():
+			return fmt.Errorf("%w: %s is %s", ErrPlayerUnavailable, name, p.Availability)
+		}
+
+		seen[name] = true
+	}
+
+	return nil
+}
+
+// apply updates the tally with the event, returning an error and leaving the
+// tally unchanged if the event is not valid for the current state.
+func (t *tally) apply(e Event) error {
+	switch e.Type {
+	case EventGameStarted:
+		if t.game.State() != GameStateNotStarted {
+			return ErrGameAlreadyStarted
+		}
+
+		t.game.periods = append(t.game.periods, Period{StartTime: e.Time})
+		t.game.StartTime = e.Time
+		t.game.EndTime = time.Time{}
+		t.lastSub = time.Time{}
+		t.stints = nil
+
+		for name := range t.players {
+			t.playerReset(name)
+		}
+
+	case EventGamePaused:
+		if t.game.State() != GameStateInProgress {
+			return ErrGameNotInProgress
+		}
+
+		t.game.periods[len(t.game.periods)-1].EndTime = e.Time
+
+		for name := range t.players {
+			t.playerSubOff(name, e.Time)
+		}
+
+	case EventGameResumed:
+		if t.game.State() != GameStatePaused {
+			return ErrGameNotPaused
+		}
+
+		t.game.periods = append(t.game.periods, Period{StartTime: e.Time})
+
+		for name := range t.players {
+			t.playerSubOff(name, e.Time)
+		}
+
+	case EventGameEnded:
+		switch t.game.State() {
+		case GameStateNotStarted:
+			return ErrGameNotStarted
+		case GameStateFinished:
+			return ErrGameFinished
+		case GameStateInProgress:
+			t.game.periods[len(t.game.periods)-1].EndTime = e.Time
+		case GameStatePaused:
+		}
+
+		t.endGame(e.Time)
+
+	case EventGameReset:
+		// archive a game reset without being ended so its statistics are kept.
+		switch t.game.State() {
+		case GameStateInProgress:
+			t.game.periods[len(t.game.periods)-1].EndTime = e.Time
+			t.endGame(e.Time)
+		case GameStatePaused:
+			t.endGame(e.Time)
+		case GameStateNotStarted, GameStateFinished:
+		}
+
+		t.game = Game{}
+		t.lastSub = time.Time{}
+		t.stints = nil
+
+		// everyone is available for the next game.
+		for name, p := range t.players {
+			p.Availability = ""
+			t.players[name] = p
+			t.playerReset(name)
+		}
+
+	case EventPlayerReset:
+		if _, ok := t.players[e.Player]; !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		t.playerReset(e.Player)
+
+	case EventPlayerSet:
+		p, ok := t.players[e.Player]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		p.PlayCount = e.PlayCount
+		p.PlayDuration = e.PlayDuration
+		t.players[e.Player] = p
+
+	case EventPlayerSubOn:
+		p, ok := t.players[e.Player]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		if !p.Available() {
+			return fmt.Errorf("%w: %s is %s", ErrPlayerUnavailable, e.Player, p.Availability)
+		}
+
+		t.playerSubOn(e.Player, e.Time, e.Position)
+		t.lastSub = e.Time
+
+	case EventPlayerSubOff:
+		if _, ok := t.players[e.Player]; !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		t.playerSubOff(e.Player, e.Time)
+		t.lastSub = e.Time
+
+	case EventPlayerAvailability:
+		p, ok := t.players[e.Player]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		if !slices.Contains(Availabilities, e.Availability) {
+			return fmt.Errorf("%w: %q", ErrInvalidAvailability, e.Availability)
+		}
+
+		if e.Availability != AvailabilityAvailable && p.Playing {
+			t.playerSubOff(e.Player, e.Time)
+			t.lastSub = e.Time
+		}
+
+		p = t.players[e.Player]
+		p.Availability = e.Availability
+
+		if e.Availability == AvailabilityAvailable {
+			p.Availability = ""
+		}
+
+		t.players[e.Player] = p
+
+	case EventPlayerAdded:
+		if err := t.checkPlayer(e.Player, e.Number, ""); err != nil {
+			return err
+		}
+
+		if e.Availability != "" && !slices.Contains(Availabilities, e.Availability) {
+			return fmt.Errorf("%w: %q", ErrInvalidAvailability, e.Availability)
+		}
+
+		t.players[e.Player] = newPlayer(Player{
+			Name:              e.Player,
+			Number:            e.Number,
+			PreferredPosition: e.Position,
+			Availability:      e.Availability,
+		})
+
+	case EventPlayerUpdated:
+		p, ok := t.players[e.Player]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		name := e.Player
+		if e.NewName != "" {
+			name = e.NewName
+		}
+
+		if err := t.checkPlayer(name, e.Number, e.Player); err != nil {
+			return err
+		}
+
+		delete(t.players, e.Player)
+
+		p.Name = name
+		p.Number = e.Number
+
+		if e.Position != "" {
+			p.PreferredPosition = e.Position
+		}
+
+		t.players[name] = p
+
+		for i := range t.stints {
+			if t.stints[i].Player == e.Player {
+				t.stints[i].Player = name
+			}
+		}
+
+		if name != e.Player {
+			t.renameHistory(e.Player, name)
+		}
+
+	case EventPlayerRemoved:
+		p, ok := t.players[e.Player]
+		if !ok {
+			return fmt.Errorf("%w: %s", ErrPlayerNotFound, e.Player)
+		}
+
+		if p.Playing {
+			return fmt.Errorf("%w: %s", ErrPlayerOnField, e.Player)
+		}
+
+		delete(t.players, e.Player)
+
+	case EventPlayersImported:
+		players := maps.Clone(t.players)
+
+		for _, p := range e.Players {
+			existing := ""
+			if _, ok := t.players[p.Name]; ok {
+				existing = p.Name
+			}
+
+			err := t.checkPlayer(p.Name, p.Number, existing)
+			if err == nil && p.Availability != "" && !slices.Contains(Availabilities, p.Availability) {
+				err = fmt.Errorf("%w: %q", ErrInvalidAvailability, p.Availability)
+			}
+
+			if err != nil {
+				t.players = players
+
+				return err
+			}
+
+			imported := newPlayer(p)
+			if existing != "" {
+				// keep the statistics of an updated player.
+				updated := t.players[p.Name]
+				updated.Number = imported.Number
+				updated.PreferredPosition = imported.PreferredPosition
+				updated.Availability = imported.Availability
+				imported = updated
+			}
+
+			t.players[p.Name] = imported
+		}
+
+		for _, p := range e.Players {
+			if imported := t.players[p.Name]; imported.Playing && !imported.Available() {
+				t.playerSubOff(p.Name, e.Time)
+				t.lastSub = e.Time
+			}
+		}
+
+	case EventSwap:
+		if err := t.checkSwap(e); err != nil {
+			return err
+		}
+
+		// players coming on fill the positions of those going off, in order.
+		positions := make([]string, len(e.On))
+		for i, name := range e.Off {
+			if p := t.players[name]; i < len(positions) && p.Playing {
+				positions[i] = p.Position
+			}
+		}
+
+		for _, name := range e.Off {
+			t.playerSubOff(name, e.Time)
+		}
+
+		for i, name := range e.On {
+			t.playerSubOn(name, e.Time, positions[i])
+		}
+
+		t.lastSub = e.Time
+
+	case EventScore:
+		if err := t.checkScore(e); err != nil {
+			return err
+		}
+
+		for _, name := range []string{e.Player, e.Assist} {
+			if _, ok := t.players[name]; name != "" && !ok {
+				return fmt.Errorf("%w: %s", ErrPlayerNotFound, name)
+			}
+		}
+
+		if e.Assist != "" && (e.Player == "" || e.Assist == e.Player) {
+			return fmt.Errorf("%w: assist %s needs another scorer", ErrInvalidScore, e.Assist)
+		}
+
+		t.game.Score += e.Points
+
+		if p, ok := t.players[e.Player]; ok {
+			p.Points += e.Points
+			t.players[e.Player] = p
+		}
+
+		if p, ok := t.players[e.Assist]; ok {
+			p.Assists++
+			t.players[e.Assist] = p
+		}
+
+	case EventOpponentScore:
+		if err := t.checkScore(e); err != nil {
+			return err
+		}
+
+		if e.Player != "" || e.Assist != "" {
+			return fmt.Errorf("%w: opponent scores have no scorer", ErrInvalidScore)
+		}
+
+		t.game.OpponentScore += e.Points
+
+	default:
+		return fmt.Errorf("unknown event type: %q", e.Type)
+	}
+
+	return nil
+}
+
+// checkPlayer returns an error unless the name and number are valid and
+// unused by any player other than the one currently named existing.
+func (t *tally) checkPlayer(name string, number int, existing string) error {
+	if name == "" || strings.Contains(name, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidPlayerName, name)
+	}
+
+	if number < 0 {
+		return fmt.Errorf("%w: %d", ErrInvalidPlayerNumber, number)
+	}
+
+	for _, p := range t.players {
+		if p.Name == existing {
+			continue
+		}
+
+		if p.Name == name {
+			return fmt.Errorf("%w: %s", ErrPlayerExists, name)
+		}
+
+		if number != 0 && p.Number == number {
+			return fmt.Errorf("%w: %s wears %d", ErrNumberTaken, p.Name, number)
+		}
+	}
+
+	return nil
+}
+
+// checkScore returns an error unless points can be scored in the current game.
+// Scores may be recorded during a break, such as one on the siren.
+func (t *tally) checkScore(e Event) error {
+	switch t.game.State() {
+	case GameStateNotStarted:
+		return ErrGameNotStarted
+	case GameStateFinished:
+		return ErrGameFinished
+	case GameStateInProgress, GameStatePaused:
+	}
+
+	if e.Points <= 0 {
+		return fmt.Errorf("%w: points must be positive, got %d", ErrInvalidScore, e.Points)
+	}
+
+	return nil
+}
+
+// endGame subs off all players and archives the game. The current period must
+// already be ended.
+func (t *tally) endGame(at time.Time) {
+	t.game.EndTime = at
+
+	for name := range t.players {
+		t.playerSubOff(name, at)
+	}
+
+	t.history = append(t.history, GameRecord{
+		StartTime:     t.game.StartTime,
+		EndTime:       t.game.EndTime,
+		Periods:       append([]Period{}, t.game.periods...),
+		Score:         t.game.Score,
+		OpponentScore: t.game.OpponentScore,
+		Players:       t.list(at),
+		Stints:        append([]Stint{}, t.stints...),
+	})
+}
+
+func (t *tally) playerReset(name string) {
+	p := t.players[name]
+	p.PlayCount = 0
+	p.PlayDuration = 0
+	p.Playing = false
+	p.PlayStarted = time.Time{}
+	p.RestStarted = time.Time{}
+	p.Points = 0
+	p.Assists = 0
+	p.Position = ""
+	p.PositionDurations = nil
+	t.players[name] = p
+}
+
+// playerSubOn starts the player playing in the position. A player already
+// playing is moving position, so their time so far is added to the position
+// they leave and their play count is unchanged. Without a position they stay
+// where they are.
+func (t *tally) playerSubOn(name string, at time.Time, position string) {
+	p := t.players[name]
+
+	if p.Playing {
+		p.addPlayDuration(at)
+		t.endStint(name, at)
+
+		if position == "" {
+			position = p.Position
+		}
+	} else {
+		p.PlayCount++
+	}
+
+	t.stints = append(t.stints, Stint{Player: name, Position: position, Start: at})
+	p.Playing = true
+	p.PlayStarted = at
+	p.Position = position
+	t.players[name] = p
+}
+
+func (t *tally) playerSubOff(name string, at time.Time) {
+	p := t.players[name]
+	p.addPlayDuration(at)
+
+	if p.Playing {
+		p.RestStarted = at
+		t.endStint(name, at)
+	}
+
+	p.Playing = false
+	p.PlayStarted = time.Time{}
+	p.Position = ""
+	t.players[name] = p
+}
+
+// renameHistory renames the player in archived games, so their season totals
+// aren't split between their old and new names. Records are copied as they may
+// be read while the tally changes.
+func (t *tally) renameHistory(from, to string) {
+	for i, gr := range t.history {
+		if !slices.ContainsFunc(gr.Players, func(p Player) bool { return p.Name == from }) {
+			continue
+		}
+
+		gr.Players = slices.Clone(gr.Players)
+		for j := range gr.Players {
+			if gr.Players[j].Name == from {
+				gr.Players[j].Name = to
+			}
+		}
+
+		gr.Stints = slices.Clone(gr.Stints)
+		for j := range gr.Stints {
+			if gr.Stints[j].Player == from {
+				gr.Stints[j].Player = to
+			}
+		}
+
+		t.history[i] = gr
+	}
+}
+
+// endStint ends the player's current stint.
+func (t *tally) endStint(name string, at time.Time) {
+	for i := len(t.stints) - 1; i >= 0; i-- {
+		if t.stints[i].Player == name && t.stints[i].End.IsZero() {
+			t.stints[i].End = at
+
+			return
+		}
+	}
+}
+
+// addPlayDuration adds the time played since the player's play started until
+// at, to their play duration and current position.
+func (p *Player) addPlayDuration(at time.Time) {
+	if p.PlayStarted.IsZero() {
+		return
+	}
+
+	d := at.Sub(p.PlayStarted)
+	p.PlayDuration = time.Duration(p.PlayDuration.Nanoseconds() + d.Nanoseconds())
+	p.addPositionDuration(d)
+}
+
+// addPositionDuration adds time played to the player's current position.
+func (p *Player) addPositionDuration(d time.Duration) {
+	if p.Position == "" {
+		return
+	}
+
+	if p.PositionDurations == nil {
+		p.PositionDurations = make(map[string]time.Duration)
+	}
+
+	p.PositionDurations[p.Position] += d
+}
+
+// onField returns the number of players playing.
+func (t *tally) onField() int {
+	var n int
+
+	for _, p := range t.players {
+		if p.Playing {
+			n++
+		}
+	}
+
+	return n
+}
+
+// list returns all players sorted by name, with the play duration of players
+// currently playing calculated up until at.
+func (t *tally) list(at time.Time) []Player {
+	players := make([]Player, 0, len(t.players))
+	for _, p := range t.players {
+		// copied so callers can read it while the tally changes.
+		p.PositionDurations = maps.Clone(p.PositionDurations)
+
+		if p.Playing {
+			d := at.Sub(p.PlayStarted)
+			p.PlayDuration = time.Duration(p.PlayDuration.Nanoseconds() + d.Nanoseconds())
+			p.addPositionDuration(d)
+		}
+
+		players = append(players, p)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return players[i].Name < players[j].Name
+	})
+
+	return players
+}
@@ -0,0 +1,408 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReplay_PlayersAt(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(10 * time.Minute), Type: EventPlayerSubOff, Player: "jane"},
+		{Time: start.Add(10 * time.Minute), Type: EventPlayerSubOn, Player: "john"},
+		{Time: start.Add(20 * time.Minute), Type: EventGamePaused},
+	}
+
+	tl, errs := replay(roster, events[:4])
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	want := []Player{
+		{Name: "jane", Number: 1, PlayCount: 1, PlayDuration: 10 * time.Minute, RestStarted: start.Add(10 * time.Minute)},
+		{Name: "john", Number: 2, PlayCount: 1, PlayDuration: 5 * time.Minute, Playing: true, PlayStarted: start.Add(10 * time.Minute)},
+	}
+
+	got := tl.list(start.Add(15 * time.Minute))
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, errs := replay(roster, append(events, events[4])); len(errs) != 1 {
+		t.Errorf("replay() pausing a paused game, got %d errors, want 1", len(errs))
+	}
+}
+
+func TestReplay_UndoRedo(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start.Add(1 * time.Minute), Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(5 * time.Minute), Type: EventPlayerSubOff, Player: "jane"},
+		{Time: start.Add(6 * time.Minute), Type: EventUndo},
+	}
+
+	// undoing the sub off leaves jane playing since her original sub on.
+	want := []Player{
+		{Name: "jane", Number: 1, PlayCount: 1, PlayDuration: 9 * time.Minute, Playing: true, PlayStarted: start.Add(1 * time.Minute)},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(10*time.Minute))); diff != "" {
+		t.Errorf("undo list() mismatch (-want +got):\n%s", diff)
+	}
+
+	// redo restores the sub off at its original time.
+	want = []Player{
+		{Name: "jane", Number: 1, PlayCount: 1, PlayDuration: 4 * time.Minute, RestStarted: start.Add(5 * time.Minute)},
+	}
+
+	tl, errs = replay(roster, append(events, Event{Time: start.Add(7 * time.Minute), Type: EventRedo}))
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(10*time.Minute))); diff != "" {
+		t.Errorf("redo list() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplay_Score(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start.Add(1 * time.Minute), Type: EventScore, Player: "jane", Assist: "john", Points: 2},
+		{Time: start.Add(2 * time.Minute), Type: EventOpponentScore, Points: 1},
+		{Time: start.Add(3 * time.Minute), Type: EventScore, Points: 1},
+		{Time: start.Add(4 * time.Minute), Type: EventScore, Player: "john", Points: 3},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	if tl.game.Score != 6 || tl.game.OpponentScore != 1 {
+		t.Errorf("score = %d - %d, want 6 - 1", tl.game.Score, tl.game.OpponentScore)
+	}
+
+	want := []Player{
+		{Name: "jane", Number: 1, Points: 2},
+		{Name: "john", Number: 2, Points: 3, Assists: 1},
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(5*time.Minute))); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	invalid := []Event{
+		{Time: start.Add(5 * time.Minute), Type: EventScore, Player: "mary", Points: 1},
+		{Time: start.Add(5 * time.Minute), Type: EventScore, Player: "jane", Assist: "jane", Points: 1},
+		{Time: start.Add(5 * time.Minute), Type: EventScore, Assist: "jane", Points: 1},
+		{Time: start.Add(5 * time.Minute), Type: EventScore, Player: "jane", Points: 0},
+		{Time: start.Add(5 * time.Minute), Type: EventOpponentScore, Player: "jane", Points: 1},
+	}
+
+	if _, errs := replay(roster, append(events, invalid...)); len(errs) != len(invalid) {
+		t.Errorf("replay() invalid scores, got %d errors, want %d", len(errs), len(invalid))
+	}
+
+	if _, errs := replay(roster, events[1:2]); len(errs) != 1 {
+		t.Errorf("replay() score before game started, got %d errors, want 1", len(errs))
+	}
+}
+
+func TestReplay_Positions(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}, {Name: "mary", Number: 3}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane", Position: "GK"},
+		{Time: start, Type: EventPlayerSubOn, Player: "john", Position: "C"},
+		// mary takes over in goal from jane.
+		{Time: start.Add(10 * time.Minute), Type: EventSwap, Off: []string{"jane"}, On: []string{"mary"}},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOff, Player: "john"},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOn, Player: "jane", Position: "C"},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	at := start.Add(20 * time.Minute)
+	want := []Player{
+		{
+			Name: "jane", Number: 1, PlayCount: 2, PlayDuration: 15 * time.Minute,
+			Playing: true, PlayStarted: start.Add(15 * time.Minute), RestStarted: start.Add(10 * time.Minute),
+			Position:          "C",
+			PositionDurations: map[string]time.Duration{"GK": 10 * time.Minute, "C": 5 * time.Minute},
+		},
+		{
+			Name: "john", Number: 2, PlayCount: 1, PlayDuration: 15 * time.Minute,
+			RestStarted:       start.Add(15 * time.Minute),
+			PositionDurations: map[string]time.Duration{"C": 15 * time.Minute},
+		},
+		{
+			Name: "mary", Number: 3, PlayCount: 1, PlayDuration: 10 * time.Minute,
+			Playing: true, PlayStarted: start.Add(10 * time.Minute),
+			Position:          "GK",
+			PositionDurations: map[string]time.Duration{"GK": 10 * time.Minute},
+		},
+	}
+
+	if diff := cmp.Diff(want, tl.list(at)); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	// listing doesn't change the time recorded for players still playing.
+	if got := tl.players["jane"].PositionDurations["C"]; got != 0 {
+		t.Errorf("jane tallied C duration = %v, want 0", got)
+	}
+}
+
+func TestReplay_MovePosition(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane", Position: "GK"},
+		{Time: start, Type: EventPlayerSubOn, Player: "john", Position: "C"},
+		// jane moves while playing, then into john's position once john is off.
+		{Time: start.Add(10 * time.Minute), Type: EventPlayerSubOn, Player: "jane", Position: "WD"},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOff, Player: "john"},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOn, Player: "jane", Position: "C"},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	want := []Player{
+		{
+			Name: "jane", Number: 1, PlayCount: 1, PlayDuration: 20 * time.Minute,
+			Playing: true, PlayStarted: start.Add(15 * time.Minute),
+			Position:          "C",
+			PositionDurations: map[string]time.Duration{"GK": 10 * time.Minute, "WD": 5 * time.Minute, "C": 5 * time.Minute},
+		},
+		{
+			Name: "john", Number: 2, PlayCount: 1, PlayDuration: 15 * time.Minute,
+			RestStarted:       start.Add(15 * time.Minute),
+			PositionDurations: map[string]time.Duration{"C": 15 * time.Minute},
+		},
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(20*time.Minute))); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	wantStints := []Stint{
+		{Player: "jane", Position: "GK", Start: start, End: start.Add(10 * time.Minute)},
+		{Player: "john", Position: "C", Start: start, End: start.Add(15 * time.Minute)},
+		{Player: "jane", Position: "WD", Start: start.Add(10 * time.Minute), End: start.Add(15 * time.Minute)},
+		{Player: "jane", Position: "C", Start: start.Add(15 * time.Minute)},
+	}
+
+	if diff := cmp.Diff(wantStints, tl.stints); diff != "" {
+		t.Errorf("stints mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReplay_InvalidSwap(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}, {Name: "mary", Number: 3}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	started := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane"},
+	}
+
+	tests := []struct {
+		name   string
+		events []Event
+		swap   Event
+	}{
+		{
+			name: "before game",
+			swap: Event{Off: []string{"jane"}, On: []string{"john"}},
+		},
+		{
+			name:   "no players",
+			events: started,
+			swap:   Event{},
+		},
+		{
+			name:   "subbed off twice",
+			events: started,
+			swap:   Event{Off: []string{"jane", "jane"}, On: []string{"john"}},
+		},
+		{
+			name:   "subbed on twice",
+			events: started,
+			swap:   Event{Off: []string{"jane"}, On: []string{"john", "john"}},
+		},
+		{
+			name:   "subbed both off and on",
+			events: started,
+			swap:   Event{Off: []string{"jane"}, On: []string{"jane"}},
+		},
+		{
+			name:   "off not playing",
+			events: started,
+			swap:   Event{Off: []string{"john"}, On: []string{"mary"}},
+		},
+		{
+			name:   "on already playing",
+			events: append(slices.Clone(started), Event{Time: start, Type: EventPlayerSubOn, Player: "john"}),
+			swap:   Event{Off: []string{"jane"}, On: []string{"john"}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			swap := tc.swap
+			swap.Time = start.Add(10 * time.Minute)
+			swap.Type = EventSwap
+
+			before, errs := replay(roster, tc.events)
+			if len(errs) != 0 {
+				t.Fatalf("replay() errors: %v", errs)
+			}
+
+			got, errs := replay(roster, append(slices.Clone(tc.events), swap))
+			if len(errs) != 1 || !errors.Is(errs[0], ErrInvalidSwap) {
+				t.Fatalf("replay() errors = %v, want %v", errs, ErrInvalidSwap)
+			}
+
+			// a rejected swap changes nothing.
+			at := start.Add(20 * time.Minute)
+			if diff := cmp.Diff(before.list(at), got.list(at)); diff != "" {
+				t.Errorf("list() mismatch (-before +after):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplay_Availability(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		// john is absent before the game starts.
+		{Time: start.Add(-time.Minute), Type: EventPlayerAvailability, Player: "john", Availability: AvailabilityAbsent},
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane"},
+		// jane is injured, subbing her off.
+		{Time: start.Add(10 * time.Minute), Type: EventPlayerAvailability, Player: "jane", Availability: AvailabilityInjured},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	want := []Player{
+		{
+			Name: "jane", Number: 1, PlayCount: 1, PlayDuration: 10 * time.Minute,
+			RestStarted: start.Add(10 * time.Minute), Availability: AvailabilityInjured,
+		},
+		{Name: "john", Number: 2, Availability: AvailabilityAbsent},
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(15*time.Minute))); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	unavailable := []Event{
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(15 * time.Minute), Type: EventSwap, On: []string{"john"}},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerAvailability, Player: "john", Availability: "late"},
+	}
+
+	if _, errs := replay(roster, append(events, unavailable...)); len(errs) != len(unavailable) {
+		t.Errorf("replay() subbing on unavailable players, got %d errors, want %d", len(errs), len(unavailable))
+	}
+
+	// everyone is available for the next game.
+	tl, errs = replay(roster, append(events, Event{Time: start.Add(20 * time.Minute), Type: EventGameReset}))
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	for _, p := range tl.list(start.Add(20 * time.Minute)) {
+		if !p.Available() {
+			t.Errorf("%s availability after reset = %s, want available", p.Name, p.Availability)
+		}
+	}
+
+	// john's absence doesn't count against his share of the season.
+	if got := seasonStats(tl.history); got[1].Share != 0 || got[0].Share != 0.5 {
+		t.Errorf("seasonStats() shares = %v, %v, want 0.5, 0", got[0].Share, got[1].Share)
+	}
+}
+
+func TestReplay_Roster(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(1 * time.Minute), Type: EventPlayerAdded, Player: "mary", Number: 3},
+		// renaming jane mid-game keeps her stats.
+		{Time: start.Add(2 * time.Minute), Type: EventPlayerUpdated, Player: "jane", NewName: "janet", Number: 7},
+		{Time: start.Add(3 * time.Minute), Type: EventPlayerUpdated, Player: "mary", Number: 4},
+		{Time: start.Add(4 * time.Minute), Type: EventPlayerRemoved, Player: "john"},
+		{Time: start.Add(5 * time.Minute), Type: EventPlayerSubOff, Player: "janet"},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	want := []Player{
+		{Name: "janet", Number: 7, PlayCount: 1, PlayDuration: 5 * time.Minute, RestStarted: start.Add(5 * time.Minute)},
+		{Name: "mary", Number: 4},
+	}
+
+	if diff := cmp.Diff(want, tl.list(start.Add(10*time.Minute))); diff != "" {
+		t.Errorf("list() mismatch (-want +got):\n%s", diff)
+	}
+
+	invalid := []Event{
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerAdded, Player: "mary"},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerAdded, Player: "bob", Number: 7},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerAdded, Player: ""},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerAdded, Player: "a/b"},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerAdded, Player: "bob", Number: -1},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerUpdated, Player: "mary", NewName: "janet"},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerUpdated, Player: "john", Number: 2},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerSubOn, Player: "mary"},
+		{Time: start.Add(6 * time.Minute), Type: EventPlayerRemoved, Player: "mary"},
+	}
+
+	// all are rejected except subbing on mary, who then can't be removed.
+	if _, errs := replay(roster, append(events, invalid...)); len(errs) != len(invalid)-1 {
+		t.Errorf("replay() invalid roster changes, got %d errors, want %d", len(errs), len(invalid)-1)
+	}
+}
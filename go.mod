@@ -8,4 +8,8 @@ require (
 	golang.org/x/sync v0.12.0
 )
 
-require github.com/a-h/templ v0.3.833
+require (
+	github.com/BurntSushi/toml v1.4.0
+	github.com/a-h/templ v0.3.833
+	gopkg.in/yaml.v3 v3.0.1
+)
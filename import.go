@@ -0,0 +1,363 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrImportColumnMissing = errors.New("import column missing")
+
+// ImportColumns names the CSV columns holding each player field, matched
+// ignoring case. Empty fields are found by the header names of common
+// team-management app exports, see importAliases.
+type ImportColumns struct {
+	// Name columns are joined with a space, such as first and last name.
+	Name         []string
+	Number       string
+	Position     string
+	Availability string
+}
+
+// importAliases are header names tried in order for columns that aren't
+// mapped.
+var importAliases = struct {
+	Name         [][]string
+	Number       []string
+	Position     []string
+	Availability []string
+}{
+	Name: [][]string{
+		{"name"}, {"player"}, {"player name"}, {"full name"},
+		{"first name", "last name"}, {"first", "last"}, {"given name", "family name"},
+	},
+	Number:       []string{"number", "no", "no.", "#", "jersey", "jersey number", "shirt", "shirt number", "uniform number"},
+	Position:     []string{"position", "pos", "preferred position"},
+	Availability: []string{"availability", "available", "status", "attendance", "rsvp"},
+}
+
+// ImportResult describes the changes made importing a roster.
+type ImportResult struct {
+	// Added and Updated are the names of new and existing players.
+	Added   []string
+	Updated []string
+	// Problems are the rows skipped, such as duplicates or invalid numbers.
+	Problems []error
+}
+
+// Summary returns a one line description of the import, such as
+// `added 12, updated 2, skipped 1`.
+func (ir ImportResult) Summary() string {
+	return fmt.Sprintf("added %d, updated %d, skipped %d", len(ir.Added), len(ir.Updated), len(ir.Problems))
+}
+
+// splitColumns returns the comma separated column names, nil when blank.
+func splitColumns(v string) []string {
+	var columns []string
+
+	for _, c := range strings.Split(v, ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			columns = append(columns, c)
+		}
+	}
+
+	return columns
+}
+
+// readRoster reads players from CSV with a header row. Rows that can't be
+// imported, such as duplicates or invalid numbers, are returned as problems
+// with their line number. Returns an error when the CSV can't be read or has
+// no name column.
+func readRoster(r io.Reader, columns ImportColumns) ([]Player, []error, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	idx, err := importIndexes(header, columns)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		players  []Player
+		problems []error
+	)
+
+	for {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read csv: %w", err)
+		}
+
+		line, _ := cr.FieldPos(0)
+
+		p, err := importPlayer(record, idx)
+		if err == nil {
+			err = checkImported(p, players)
+		}
+
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Errorf("line %d: %w", line, err))
+		case p.Name != "":
+			players = append(players, p)
+		}
+	}
+
+	return players, problems, nil
+}
+
+// importIndex holds the index of each column in a CSV row, -1 when missing.
+type importIndex struct {
+	name         []int
+	number       int
+	position     int
+	availability int
+}
+
+// importIndexes finds the mapped or aliased columns in the header.
+func importIndexes(header []string, columns ImportColumns) (importIndex, error) {
+	find := func(name string) int {
+		return slices.IndexFunc(header, func(h string) bool {
+			return strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name))
+		})
+	}
+
+	findAny := func(mapped string, aliases []string) (int, error) {
+		if mapped != "" {
+			if i := find(mapped); i >= 0 {
+				return i, nil
+			}
+
+			return -1, fmt.Errorf("%w: %q", ErrImportColumnMissing, mapped)
+		}
+
+		for _, alias := range aliases {
+			if i := find(alias); i >= 0 {
+				return i, nil
+			}
+		}
+
+		return -1, nil
+	}
+
+	var (
+		idx importIndex
+		err error
+	)
+
+	names := importAliases.Name
+	if len(columns.Name) > 0 {
+		names = [][]string{columns.Name}
+	}
+
+	for _, cols := range names {
+		idx.name = make([]int, 0, len(cols))
+
+		for _, col := range cols {
+			if i := find(col); i >= 0 {
+				idx.name = append(idx.name, i)
+			}
+		}
+
+		if len(idx.name) == len(cols) {
+			break
+		}
+
+		idx.name = nil
+	}
+
+	if idx.name == nil {
+		return importIndex{}, fmt.Errorf("%w: name, found %s", ErrImportColumnMissing, strings.Join(header, ", "))
+	}
+
+	if idx.number, err = findAny(columns.Number, importAliases.Number); err != nil {
+		return importIndex{}, err
+	}
+
+	if idx.position, err = findAny(columns.Position, importAliases.Position); err != nil {
+		return importIndex{}, err
+	}
+
+	if idx.availability, err = findAny(columns.Availability, importAliases.Availability); err != nil {
+		return importIndex{}, err
+	}
+
+	return idx, nil
+}
+
+// importPlayer returns the player in the CSV record, with an empty name for a
+// blank row.
+func importPlayer(record []string, idx importIndex) (Player, error) {
+	field := func(i int) string {
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+
+		return strings.TrimSpace(record[i])
+	}
+
+	names := make([]string, 0, len(idx.name))
+	for _, i := range idx.name {
+		if v := field(i); v != "" {
+			names = append(names, v)
+		}
+	}
+
+	p := Player{
+		Name:              strings.Join(names, " "),
+		PreferredPosition: field(idx.position),
+	}
+
+	if v := strings.TrimPrefix(field(idx.number), "#"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return Player{}, fmt.Errorf("%w: %q for %s", ErrInvalidPlayerNumber, field(idx.number), p.Name)
+		}
+
+		p.Number = n
+	}
+
+	availability, err := parseAvailability(field(idx.availability))
+	if err != nil {
+		return Player{}, fmt.Errorf("%w for %s", err, p.Name)
+	}
+
+	p.Availability = availability
+
+	if p.Name == "" && (p.Number != 0 || p.PreferredPosition != "") {
+		return Player{}, fmt.Errorf("%w: %q", ErrInvalidPlayerName, p.Name)
+	}
+
+	return p, nil
+}
+
+// checkImported returns an error if the player has the name or number of a
+// player already imported.
+func checkImported(p Player, imported []Player) error {
+	if strings.Contains(p.Name, "/") {
+		return fmt.Errorf("%w: %q", ErrInvalidPlayerName, p.Name)
+	}
+
+	for _, other := range imported {
+		if other.Name == p.Name {
+			return fmt.Errorf("%w: %s is listed twice", ErrPlayerExists, p.Name)
+		}
+
+		if p.Number != 0 && other.Number == p.Number {
+			return fmt.Errorf("%w: %s and %s wear %d", ErrNumberTaken, other.Name, p.Name, p.Number)
+		}
+	}
+
+	return nil
+}
+
+// parseAvailability reads the availability written by a coach or a
+// team-management app, such as `injured` or an RSVP of `no`. Blank means
+// unknown and returns empty.
+func parseAvailability(v string) (Availability, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "":
+		return "", nil
+	case "available", "yes", "y", "going", "attending", "maybe":
+		return AvailabilityAvailable, nil
+	case "absent", "no", "n", "not going", "unavailable":
+		return AvailabilityAbsent, nil
+	case "injured":
+		return AvailabilityInjured, nil
+	case "sent off", "sent_off", "suspended":
+		return AvailabilitySentOff, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidAvailability, v)
+	}
+}
+
+// mergeRoster adds imported players to the roster, updating the number,
+// position and availability of players with the same name, so a roster can be
+// imported again after changes. Blank imported fields keep the current value.
+// Players whose number is worn by another player, or whose position isn't one
+// of positions when positions are known, are skipped and returned as problems.
+func mergeRoster(roster, imported []Player, positions []string) ([]Player, ImportResult) {
+	merged := slices.Clone(roster)
+
+	var result ImportResult
+
+	for _, p := range imported {
+		if p.PreferredPosition != "" && len(positions) > 0 && !slices.Contains(positions, p.PreferredPosition) {
+			result.Problems = append(result.Problems, fmt.Errorf("%w: %q for %s", ErrInvalidPosition, p.PreferredPosition, p.Name))
+
+			continue
+		}
+
+		i := slices.IndexFunc(merged, func(m Player) bool { return m.Name == p.Name })
+
+		updated := p
+		if i >= 0 {
+			updated = merged[i]
+
+			if p.Number != 0 {
+				updated.Number = p.Number
+			}
+
+			if p.PreferredPosition != "" {
+				updated.PreferredPosition = p.PreferredPosition
+			}
+
+			if p.Availability != "" {
+				updated.Availability = p.Availability
+			}
+		}
+
+		if updated.Availability == AvailabilityAvailable {
+			updated.Availability = ""
+		}
+
+		if updated.Number != 0 {
+			if j := slices.IndexFunc(merged, func(m Player) bool {
+				return m.Name != p.Name && m.Number == updated.Number
+			}); j >= 0 {
+				result.Problems = append(result.Problems, fmt.Errorf("%w: %s wears %d, skipping %s",
+					ErrNumberTaken, merged[j].Name, updated.Number, p.Name))
+
+				continue
+			}
+		}
+
+		if i >= 0 {
+			if sameRosterDetails(merged[i], updated) {
+				continue
+			}
+
+			merged[i] = updated
+			result.Updated = append(result.Updated, p.Name)
+
+			continue
+		}
+
+		merged = append(merged, updated)
+		result.Added = append(result.Added, p.Name)
+	}
+
+	return merged, result
+}
+
+// sameRosterDetails returns true when the players have the same number,
+// position and availability.
+func sameRosterDetails(a, b Player) bool {
+	return a.Number == b.Number &&
+		a.PreferredPosition == b.PreferredPosition &&
+		a.Availability == b.Availability
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestReadRoster(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		columns  ImportColumns
+		want     []Player
+		problems []error
+		err      error
+	}{
+		{
+			name: "gosubs headers",
+			csv: "Name,Number,Position,Availability\n" +
+				"jane,1,GK,\n" +
+				"john,#2,,injured\n" +
+				"\n" +
+				"mary,,C,yes\n",
+			want: []Player{
+				{Name: "jane", Number: 1, PreferredPosition: "GK"},
+				{Name: "john", Number: 2, Availability: AvailabilityInjured},
+				{Name: "mary", PreferredPosition: "C", Availability: AvailabilityAvailable},
+			},
+		},
+		{
+			name: "team app export",
+			csv: "First Name,Last Name,Jersey Number,RSVP\n" +
+				"Jane,Smith,7,No\n" +
+				"John,Citizen,8,Going\n",
+			want: []Player{
+				{Name: "Jane Smith", Number: 7, Availability: AvailabilityAbsent},
+				{Name: "John Citizen", Number: 8, Availability: AvailabilityAvailable},
+			},
+		},
+		{
+			name:    "mapped columns",
+			csv:     "Kid,Shirt,Spot\njane,1,GK\n",
+			columns: ImportColumns{Name: []string{"kid"}, Number: "SHIRT", Position: "spot"},
+			want:    []Player{{Name: "jane", Number: 1, PreferredPosition: "GK"}},
+		},
+		{
+			name: "duplicates and invalid numbers",
+			csv: "name,number,availability\n" +
+				"jane,1,\n" +
+				"jane,3,\n" +
+				"john,1,\n" +
+				"mary,x,\n" +
+				"bob,-4,\n" +
+				"sam,5,maybe not\n" +
+				",6,\n",
+			want: []Player{{Name: "jane", Number: 1}},
+			problems: []error{
+				ErrPlayerExists,
+				ErrNumberTaken,
+				ErrInvalidPlayerNumber,
+				ErrInvalidPlayerNumber,
+				ErrInvalidAvailability,
+				ErrInvalidPlayerName,
+			},
+		},
+		{
+			name: "missing name column",
+			csv:  "number\n1\n",
+			err:  ErrImportColumnMissing,
+		},
+		{
+			name:    "missing mapped column",
+			csv:     "name,number\njane,1\n",
+			columns: ImportColumns{Position: "position"},
+			err:     ErrImportColumnMissing,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, problems, err := readRoster(strings.NewReader(tc.csv), tc.columns)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("readRoster() error = %v, want %v", err, tc.err)
+			}
+
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("readRoster() mismatch (-want +got):\n%s", diff)
+			}
+
+			if len(problems) != len(tc.problems) {
+				t.Fatalf("readRoster() problems = %v, want %v", problems, tc.problems)
+			}
+
+			for i, problem := range problems {
+				if !errors.Is(problem, tc.problems[i]) {
+					t.Errorf("readRoster() problem %d = %v, want %v", i, problem, tc.problems[i])
+				}
+			}
+		})
+	}
+}
+
+func TestMergeRoster(t *testing.T) {
+	roster := []Player{
+		{Name: "jane", Number: 1, PreferredPosition: "GK"},
+		{Name: "john", Number: 2},
+		{Name: "mary", Number: 3},
+	}
+
+	imported := []Player{
+		// blank fields keep the current value.
+		{Name: "jane", Availability: AvailabilityAbsent},
+		{Name: "john", Number: 2},
+		{Name: "mary", Number: 1},
+		{Name: "bob", Number: 4, PreferredPosition: "C", Availability: AvailabilityAvailable},
+		{Name: "sam", PreferredPosition: "WD"},
+	}
+
+	merged, result := mergeRoster(roster, imported, []string{"GK", "C"})
+
+	want := []Player{
+		{Name: "jane", Number: 1, PreferredPosition: "GK", Availability: AvailabilityAbsent},
+		{Name: "john", Number: 2},
+		{Name: "mary", Number: 3},
+		{Name: "bob", Number: 4, PreferredPosition: "C"},
+	}
+
+	if diff := cmp.Diff(want, merged); diff != "" {
+		t.Errorf("mergeRoster() mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"bob"}, result.Added); diff != "" {
+		t.Errorf("mergeRoster() added mismatch (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff([]string{"jane"}, result.Updated); diff != "" {
+		t.Errorf("mergeRoster() updated mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(result.Problems) != 2 ||
+		!errors.Is(result.Problems[0], ErrNumberTaken) ||
+		!errors.Is(result.Problems[1], ErrInvalidPosition) {
+		t.Errorf("mergeRoster() problems = %v, want number taken and invalid position", result.Problems)
+	}
+}
+
+func TestSubber_ImportPlayers(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}},
+	})
+
+	result := s.ImportPlayers([]Player{
+		{Name: "jane", Number: 7, Availability: AvailabilityInjured},
+		{Name: "mary", Number: 3, PreferredPosition: "GK", Availability: AvailabilityAbsent},
+		{Name: "bob", Number: 2},
+	})
+
+	if diff := cmp.Diff(ImportResult{Added: []string{"mary"}, Updated: []string{"jane"}}, result,
+		cmp.FilterPath(func(p cmp.Path) bool { return p.Last().String() == ".Problems" }, cmp.Ignore())); diff != "" {
+		t.Errorf("ImportPlayers() mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(result.Problems) != 1 || !errors.Is(result.Problems[0], ErrNumberTaken) {
+		t.Errorf("ImportPlayers() problems = %v, want bob's number taken", result.Problems)
+	}
+
+	want := []Player{
+		{Name: "jane", Number: 7, Availability: AvailabilityInjured},
+		{Name: "john", Number: 2},
+		{Name: "mary", Number: 3, PreferredPosition: "GK", Availability: AvailabilityAbsent},
+	}
+
+	if diff := cmp.Diff(want, s.ListPlayers()); diff != "" {
+		t.Errorf("ListPlayers() mismatch (-want +got):\n%s", diff)
+	}
+
+	// the whole import is undone in one step.
+	if err := s.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+
+	want = []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+
+	if diff := cmp.Diff(want, s.ListPlayers()); diff != "" {
+		t.Errorf("undo ListPlayers() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrNoGame = errors.New("no game played")
+
+// Stint is a continuous spell on the field by a player.
+type Stint struct {
+	Player string `json:"player"`
+	// Position filled, empty if unknown.
+	Position string    `json:"position,omitempty"`
+	Start    time.Time `json:"start"`
+	// End is zero while the player is still on the field.
+	End time.Time `json:"end"`
+}
+
+// Duration returns the length of the stint, up until at while it continues.
+func (s Stint) Duration(at time.Time) time.Duration {
+	end := s.End
+	if end.IsZero() {
+		end = at
+	}
+
+	return end.Sub(s.Start)
+}
+
+// GameReport is a game's score and player statistics, broken down by period,
+// for exporting and printing after the game.
+type GameReport struct {
+	Team string
+	// Game is the current or archived game, with Players and Stints calculated
+	// up until At for a game in progress.
+	Game GameRecord
+	At   time.Time
+	// Players in the order of Game.Players.
+	Players []PlayerReport
+}
+
+// PlayerReport is a player's statistics for a game.
+type PlayerReport struct {
+	Player
+	// PeriodDurations is the time played in each period of the game.
+	PeriodDurations []time.Duration
+	Stints          []Stint
+}
+
+// newGameReport breaks down the game's statistics by period as at a time.
+func newGameReport(team string, gr GameRecord, at time.Time) GameReport {
+	players := make([]PlayerReport, 0, len(gr.Players))
+
+	for _, p := range gr.Players {
+		pr := PlayerReport{Player: p, PeriodDurations: make([]time.Duration, len(gr.Periods))}
+
+		for _, st := range gr.Stints {
+			if st.Player != p.Name {
+				continue
+			}
+
+			pr.Stints = append(pr.Stints, st)
+
+			for i, period := range gr.Periods {
+				pr.PeriodDurations[i] += overlap(st.Start, orAt(st.End, at), period.StartTime, orAt(period.EndTime, at))
+			}
+		}
+
+		players = append(players, pr)
+	}
+
+	return GameReport{Team: team, Game: gr, At: at, Players: players}
+}
+
+// orAt returns t, or at when t is zero for something still underway.
+func orAt(t, at time.Time) time.Time {
+	if t.IsZero() {
+		return at
+	}
+
+	return t
+}
+
+// overlap returns the time two spans have in common.
+func overlap(start1, end1, start2, end2 time.Time) time.Duration {
+	start := start1
+	if start2.After(start) {
+		start = start2
+	}
+
+	end := end1
+	if end2.Before(end) {
+		end = end2
+	}
+
+	return max(end.Sub(start), 0)
+}
+
+// GameReport returns the report of an archived game, numbered from 1 oldest
+// first. Zero is the current game, or the most recently archived game when a
+// new game hasn't started. Returns ErrNoGame when there is no such game.
+func (s *Subber) GameReport(game int) (GameReport, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	at := time.Now()
+	g := s.tally.game
+	history := s.tally.history
+
+	if game == 0 && g.State() != GameStateNotStarted {
+		gr := GameRecord{
+			StartTime:     g.StartTime,
+			EndTime:       g.EndTime,
+			Periods:       append([]Period{}, g.periods...),
+			Score:         g.Score,
+			OpponentScore: g.OpponentScore,
+			Players:       s.tally.list(at),
+			Stints:        append([]Stint{}, s.tally.stints...),
+		}
+
+		return newGameReport(s.name, gr, at), nil
+	}
+
+	if game == 0 {
+		game = len(history)
+	}
+
+	if game < 1 || game > len(history) {
+		return GameReport{}, ErrNoGame
+	}
+
+	return newGameReport(s.name, history[game-1], at), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewGameReport(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	events := []Event{
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane", Position: "GK"},
+		{Time: start.Add(5 * time.Minute), Type: EventPlayerSubOn, Player: "john"},
+		{Time: start.Add(10 * time.Minute), Type: EventGamePaused},
+		{Time: start.Add(15 * time.Minute), Type: EventGameResumed},
+		{Time: start.Add(15 * time.Minute), Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(25 * time.Minute), Type: EventGameEnded},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	report := newGameReport("tigers", tl.history[0], start.Add(time.Hour))
+
+	type summary struct {
+		Name            string
+		PeriodDurations []time.Duration
+		Stints          []Stint
+	}
+
+	want := []summary{
+		{
+			Name:            "jane",
+			PeriodDurations: []time.Duration{10 * time.Minute, 10 * time.Minute},
+			Stints: []Stint{
+				{Player: "jane", Position: "GK", Start: start, End: start.Add(10 * time.Minute)},
+				{Player: "jane", Start: start.Add(15 * time.Minute), End: start.Add(25 * time.Minute)},
+			},
+		},
+		{
+			Name:            "john",
+			PeriodDurations: []time.Duration{5 * time.Minute, 0},
+			Stints: []Stint{
+				{Player: "john", Start: start.Add(5 * time.Minute), End: start.Add(10 * time.Minute)},
+			},
+		},
+	}
+
+	got := make([]summary, 0, len(report.Players))
+	for _, p := range report.Players {
+		got = append(got, summary{Name: p.Name, PeriodDurations: p.PeriodDurations, Stints: p.Stints})
+	}
+
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newGameReport() mismatch (-want +got):\n%s", diff)
+	}
+}
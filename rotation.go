@@ -0,0 +1,113 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+// minSwapGap is the smallest difference in play duration worth suggesting a
+// swap for, avoiding constant churn between players with near equal time.
+const minSwapGap = time.Minute
+
+// Swap is a substitution of one player off the field for another. Off is empty
+// when there is a free position to fill and On is empty when there are more
+// players on the field than positions.
+type Swap struct {
+	Off string
+	On  string
+}
+
+// rollingSwap returns the players to swap for a rolling sub: the player on
+// the field for the longest current stint, and the player resting longest on
+// the bench, with players yet to play resting longest. Unavailable players
+// stay on the bench. Ties go to the player with the most, or least, play
+// duration.
+func rollingSwap(players []Player) Swap {
+	var sw Swap
+
+	var off, on *Player
+
+	for i := range players {
+		p := &players[i]
+
+		if p.Playing {
+			if off == nil || p.PlayStarted.Before(off.PlayStarted) ||
+				(p.PlayStarted.Equal(off.PlayStarted) && p.PlayDuration > off.PlayDuration) {
+				off = p
+			}
+
+			continue
+		}
+
+		if !p.Available() {
+			continue
+		}
+
+		if on == nil || p.RestStarted.Before(on.RestStarted) ||
+			(p.RestStarted.Equal(on.RestStarted) && p.PlayDuration < on.PlayDuration) {
+			on = p
+		}
+	}
+
+	if off != nil {
+		sw.Off = off.Name
+	}
+
+	if on != nil {
+		sw.On = on.Name
+	}
+
+	return sw
+}
+
+// suggestSwaps recommends substitutions that even out play duration, subbing
+// off the longest playing players for the shortest resting players. A
+// positive onField fills free positions and removes excess players first.
+func suggestSwaps(players []Player, onField int) []Swap {
+	var playing, bench []Player
+
+	for _, p := range players {
+		switch {
+		case p.Playing:
+			playing = append(playing, p)
+		case p.Available():
+			bench = append(bench, p)
+		}
+	}
+
+	// longest playing first.
+	sort.SliceStable(playing, func(i, j int) bool {
+		return playing[i].PlayDuration > playing[j].PlayDuration
+	})
+
+	// least played first.
+	sort.SliceStable(bench, func(i, j int) bool {
+		return bench[i].PlayDuration < bench[j].PlayDuration
+	})
+
+	var swaps []Swap
+
+	if onField > 0 {
+		for len(playing) > onField {
+			swaps = append(swaps, Swap{Off: playing[0].Name})
+			playing = playing[1:]
+		}
+
+		for free := onField - len(playing); free > 0 && len(bench) > 0; free-- {
+			swaps = append(swaps, Swap{On: bench[0].Name})
+			bench = bench[1:]
+		}
+	}
+
+	for len(playing) > 0 && len(bench) > 0 {
+		if playing[0].PlayDuration-bench[0].PlayDuration < minSwapGap {
+			break
+		}
+
+		swaps = append(swaps, Swap{Off: playing[0].Name, On: bench[0].Name})
+		playing = playing[1:]
+		bench = bench[1:]
+	}
+
+	return swaps
+}
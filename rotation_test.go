@@ -0,0 +1,127 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSuggestSwaps(t *testing.T) {
+	tests := map[string]struct {
+		players []Player
+		onField int
+		want    []Swap
+	}{
+		"longest playing off for least played": {
+			players: []Player{
+				{Name: "jane", PlayDuration: 10 * time.Minute, Playing: true},
+				{Name: "john", PlayDuration: 4 * time.Minute, Playing: true},
+				{Name: "mary", PlayDuration: 2 * time.Minute},
+				{Name: "bob", PlayDuration: 5 * time.Minute},
+			},
+			onField: 2,
+			want:    []Swap{{Off: "jane", On: "mary"}},
+		},
+		"fill free positions": {
+			players: []Player{
+				{Name: "jane", PlayDuration: 1 * time.Minute, Playing: true},
+				{Name: "mary", PlayDuration: 3 * time.Minute},
+				{Name: "bob", PlayDuration: 5 * time.Minute},
+			},
+			onField: 2,
+			want:    []Swap{{On: "mary"}},
+		},
+		"too many on field": {
+			players: []Player{
+				{Name: "jane", PlayDuration: 1 * time.Minute, Playing: true},
+				{Name: "john", PlayDuration: 2 * time.Minute, Playing: true},
+			},
+			onField: 1,
+			want:    []Swap{{Off: "john"}},
+		},
+		"unavailable players stay on the bench": {
+			players: []Player{
+				{Name: "jane", PlayDuration: 10 * time.Minute, Playing: true},
+				{Name: "mary", Availability: AvailabilityAbsent},
+				{Name: "bob", PlayDuration: 2 * time.Minute, Availability: AvailabilityInjured},
+				{Name: "sue", PlayDuration: 3 * time.Minute},
+			},
+			onField: 2,
+			want:    []Swap{{On: "sue"}},
+		},
+		"even time": {
+			players: []Player{
+				{Name: "jane", PlayDuration: 5 * time.Minute, Playing: true},
+				{Name: "mary", PlayDuration: 5 * time.Minute},
+			},
+			want: nil,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, suggestSwaps(tc.players, tc.onField)); diff != "" {
+				t.Errorf("suggestSwaps() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRollingSwap(t *testing.T) {
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		players []Player
+		want    Swap
+	}{
+		"longest stint off, longest rest on": {
+			players: []Player{
+				{Name: "jane", Playing: true, PlayStarted: start.Add(4 * time.Minute)},
+				{Name: "john", Playing: true, PlayStarted: start},
+				{Name: "mary", RestStarted: start.Add(4 * time.Minute)},
+				{Name: "bob", RestStarted: start.Add(2 * time.Minute)},
+			},
+			want: Swap{Off: "john", On: "bob"},
+		},
+		"yet to play rests longest": {
+			players: []Player{
+				{Name: "jane", Playing: true, PlayStarted: start},
+				{Name: "mary", RestStarted: start.Add(4 * time.Minute), PlayDuration: time.Minute},
+				{Name: "bob"},
+			},
+			want: Swap{Off: "jane", On: "bob"},
+		},
+		"ties go to play duration": {
+			players: []Player{
+				{Name: "jane", Playing: true, PlayStarted: start, PlayDuration: 2 * time.Minute},
+				{Name: "john", Playing: true, PlayStarted: start, PlayDuration: 3 * time.Minute},
+				{Name: "mary", PlayDuration: 2 * time.Minute},
+				{Name: "bob", PlayDuration: time.Minute},
+			},
+			want: Swap{Off: "john", On: "bob"},
+		},
+		"unavailable players stay on the bench": {
+			players: []Player{
+				{Name: "jane", Playing: true, PlayStarted: start},
+				{Name: "mary", RestStarted: start.Add(4 * time.Minute)},
+				{Name: "bob", Availability: AvailabilitySentOff},
+			},
+			want: Swap{Off: "jane", On: "mary"},
+		},
+		"empty bench": {
+			players: []Player{
+				{Name: "jane", Playing: true, PlayStarted: start},
+			},
+			want: Swap{Off: "jane"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, rollingSwap(tc.players)); diff != "" {
+				t.Errorf("rollingSwap() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"slices"
+	"sort"
+	"time"
+)
+
+// GameRecord is an archived game with the statistics of each player at the
+// end of the game.
+type GameRecord struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Periods   []Period  `json:"periods"`
+	// Score and OpponentScore are the final points of each side.
+	Score         int      `json:"score"`
+	OpponentScore int      `json:"opponentScore"`
+	Players       []Player `json:"players"`
+	// Stints played by each player, in the order they started.
+	Stints []Stint `json:"stints"`
+}
+
+// Duration returns the total time played across all periods, excluding breaks.
+func (gr GameRecord) Duration() time.Duration {
+	var d time.Duration
+
+	for _, p := range gr.Periods {
+		d += p.EndTime.Sub(p.StartTime)
+	}
+
+	return d
+}
+
+// PlayerSeason is a player's statistics totalled across archived games.
+type PlayerSeason struct {
+	Name   string
+	Number int
+	// Games is the number of games the player was subbed on in.
+	Games        int
+	PlayCount    int
+	PlayDuration time.Duration
+	Points       int
+	Assists      int
+	// PositionDurations is the time played in each position.
+	PositionDurations map[string]time.Duration
+	// Share is the average proportion of game time played, from 0 to 1, across
+	// all archived games the player was in the roster for and not absent from.
+	Share float64
+}
+
+// seasonPositions returns the configured positions followed by any others
+// played during the season, such as positions since removed.
+func seasonPositions(configured []string, stats []PlayerSeason) []string {
+	positions := append([]string{}, configured...)
+
+	var others []string
+
+	for _, ps := range stats {
+		for pos := range ps.PositionDurations {
+			if !slices.Contains(positions, pos) && !slices.Contains(others, pos) {
+				others = append(others, pos)
+			}
+		}
+	}
+
+	sort.Strings(others)
+
+	return append(positions, others...)
+}
+
+// seasonStats totals player statistics across archived games, sorted by name.
+func seasonStats(history []GameRecord) []PlayerSeason {
+	totals := make(map[string]*PlayerSeason)
+	rostered := make(map[string]int)
+
+	for _, gr := range history {
+		duration := gr.Duration()
+
+		for _, p := range gr.Players {
+			ps, ok := totals[p.Name]
+			if !ok {
+				ps = &PlayerSeason{Name: p.Name}
+				totals[p.Name] = ps
+			}
+
+			ps.Number = p.Number
+
+			if p.Availability == AvailabilityAbsent {
+				continue
+			}
+
+			ps.PlayCount += p.PlayCount
+			ps.PlayDuration += p.PlayDuration
+			ps.Points += p.Points
+			ps.Assists += p.Assists
+
+			for pos, d := range p.PositionDurations {
+				if ps.PositionDurations == nil {
+					ps.PositionDurations = make(map[string]time.Duration)
+				}
+
+				ps.PositionDurations[pos] += d
+			}
+
+			rostered[p.Name]++
+
+			if p.PlayCount > 0 {
+				ps.Games++
+			}
+
+			if duration > 0 {
+				ps.Share += float64(p.PlayDuration) / float64(duration)
+			}
+		}
+	}
+
+	stats := make([]PlayerSeason, 0, len(totals))
+	for name, ps := range totals {
+		if rostered[name] > 0 {
+			ps.Share /= float64(rostered[name])
+		}
+
+		stats = append(stats, *ps)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+
+	return stats
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestReplay_SeasonStats(t *testing.T) {
+	roster := []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}
+	start := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+	next := start.Add(7 * 24 * time.Hour)
+
+	events := []Event{
+		// jane plays the whole of a 40 minute game.
+		{Time: start, Type: EventGameStarted},
+		{Time: start, Type: EventPlayerSubOn, Player: "jane"},
+		{Time: start.Add(10 * time.Minute), Type: EventScore, Player: "jane", Points: 1},
+		{Time: start.Add(40 * time.Minute), Type: EventGameEnded},
+		{Time: start.Add(50 * time.Minute), Type: EventGameReset},
+		// both play half of a 40 minute game that is reset without being ended.
+		{Time: next, Type: EventGameStarted},
+		{Time: next, Type: EventPlayerSubOn, Player: "jane"},
+		{Time: next.Add(20 * time.Minute), Type: EventPlayerSubOff, Player: "jane"},
+		{Time: next.Add(20 * time.Minute), Type: EventPlayerSubOn, Player: "john"},
+		{Time: next.Add(30 * time.Minute), Type: EventScore, Player: "john", Assist: "jane", Points: 1},
+		{Time: next.Add(35 * time.Minute), Type: EventOpponentScore, Points: 1},
+		{Time: next.Add(40 * time.Minute), Type: EventGameReset},
+	}
+
+	tl, errs := replay(roster, events)
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	if got := len(tl.history); got != 2 {
+		t.Fatalf("archived games = %d, want 2", got)
+	}
+
+	if gr := tl.history[1]; gr.Score != 1 || gr.OpponentScore != 1 {
+		t.Errorf("archived score = %d - %d, want 1 - 1", gr.Score, gr.OpponentScore)
+	}
+
+	want := []PlayerSeason{
+		{Name: "jane", Number: 1, Games: 2, PlayCount: 2, PlayDuration: 60 * time.Minute, Points: 1, Assists: 1, Share: 0.75},
+		{Name: "john", Number: 2, Games: 1, PlayCount: 1, PlayDuration: 20 * time.Minute, Points: 1, Share: 0.25},
+	}
+
+	if diff := cmp.Diff(want, seasonStats(tl.history), cmpopts.EquateApprox(0, 0.001)); diff != "" {
+		t.Errorf("seasonStats() mismatch (-want +got):\n%s", diff)
+	}
+
+	// renaming a player keeps their season totals together.
+	tl, errs = replay(roster, append(events, Event{
+		Time: next.Add(time.Hour), Type: EventPlayerUpdated, Player: "jane", NewName: "janet", Number: 1,
+	}))
+	if len(errs) != 0 {
+		t.Fatalf("replay() errors: %v", errs)
+	}
+
+	want[0].Name = "janet"
+
+	if diff := cmp.Diff(want, seasonStats(tl.history), cmpopts.EquateApprox(0, 0.001)); diff != "" {
+		t.Errorf("renamed seasonStats() mismatch (-want +got):\n%s", diff)
+	}
+
+	if got := tl.history[0].Stints[0].Player; got != "janet" {
+		t.Errorf("archived stint player = %q, want janet", got)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	shareKeyFile = "share.key"
+	shareKeySize = 32
+	// shareMACSize is the number of bytes of the signature kept in a token,
+	// shortening links while remaining unguessable.
+	shareMACSize = 16
+)
+
+var ErrInvalidShareToken = errors.New("invalid share token")
+
+// sharer issues and checks the tokens of spectator links. A token names the
+// team, and optionally a single player, signed so it can't be guessed or
+// altered to show another team or player.
+type sharer struct {
+	key []byte
+}
+
+// sharePayload is what a share link shows.
+type sharePayload struct {
+	Team string
+	// Player shown, empty for the whole team.
+	Player string
+	// Issued is when the link was made, so the player can be followed through
+	// later renames. Zero for links made before it was recorded.
+	Issued time.Time
+}
+
+// newSharer returns a sharer with the key saved in dir, generating one if none
+// exists. Deleting the key revokes all links. An empty dir keeps the key in
+// memory only, so links stop working after a restart.
+func newSharer(dir string) (*sharer, error) {
+	if dir == "" {
+		key, err := newShareKey()
+		if err != nil {
+			return nil, err
+		}
+
+		return &sharer{key: key}, nil
+	}
+
+	path := filepath.Join(dir, shareKeyFile)
+
+	key, err := os.ReadFile(path)
+	if err == nil && len(key) == shareKeySize {
+		return &sharer{key: key}, nil
+	}
+
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to read share key: %w", err)
+	}
+
+	key, err = newShareKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create share key directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, key, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to save share key: %w", err)
+	}
+
+	return &sharer{key: key}, nil
+}
+
+func newShareKey() ([]byte, error) {
+	key := make([]byte, shareKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate share key: %w", err)
+	}
+
+	return key, nil
+}
+
+// token returns the token for the team, showing only the player unless empty.
+func (sh *sharer) token(team, player string, issued time.Time) string {
+	payload := []byte(team + "\n" + player + "\n" + strconv.FormatInt(issued.UnixNano(), 10))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(sh.sign(payload))
+}
+
+// parse returns the payload of a token issued by token.
+func (sh *sharer) parse(token string) (sharePayload, error) {
+	encPayload, encMAC, ok := strings.Cut(token, ".")
+	if !ok {
+		return sharePayload{}, ErrInvalidShareToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encPayload)
+	if err != nil {
+		return sharePayload{}, ErrInvalidShareToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(encMAC)
+	if err != nil || !hmac.Equal(mac, sh.sign(payload)) {
+		return sharePayload{}, ErrInvalidShareToken
+	}
+
+	team, rest, ok := strings.Cut(string(payload), "\n")
+	if !ok {
+		return sharePayload{}, ErrInvalidShareToken
+	}
+
+	p := sharePayload{Team: team, Player: rest}
+
+	// earlier tokens end with the player.
+	if player, issued, ok := strings.Cut(rest, "\n"); ok {
+		ns, err := strconv.ParseInt(issued, 10, 64)
+		if err != nil {
+			return sharePayload{}, ErrInvalidShareToken
+		}
+
+		p.Player, p.Issued = player, time.Unix(0, ns)
+	}
+
+	return p, nil
+}
+
+func (sh *sharer) sign(payload []byte) []byte {
+	h := hmac.New(sha256.New, sh.key)
+	h.Write(payload)
+
+	return h.Sum(nil)[:shareMACSize]
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSharer_Token(t *testing.T) {
+	sh, err := newSharer(t.TempDir())
+	if err != nil {
+		t.Fatalf("newSharer() error: %v", err)
+	}
+
+	issued := time.Date(2025, 3, 1, 9, 0, 0, 1, time.UTC)
+
+	for _, player := range []string{"", "jane"} {
+		got, err := sh.parse(sh.token("tigers", player, issued))
+		if err != nil {
+			t.Fatalf("parse() error: %v", err)
+		}
+
+		want := sharePayload{Team: "tigers", Player: player, Issued: issued}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("parse() mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// tokens made before the issued time was added are still valid.
+	payload := []byte("tigers\njane")
+	earlier := base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(sh.sign(payload))
+
+	if got, err := sh.parse(earlier); err != nil || got != (sharePayload{Team: "tigers", Player: "jane"}) {
+		t.Errorf("parse(earlier) = %+v, %v, want tigers and jane", got, err)
+	}
+
+	other, err := newSharer("")
+	if err != nil {
+		t.Fatalf("newSharer() error: %v", err)
+	}
+
+	// jane's signature on a token for john.
+	john, _, _ := strings.Cut(sh.token("tigers", "john", issued), ".")
+	_, janeMAC, _ := strings.Cut(sh.token("tigers", "jane", issued), ".")
+	tampered := john + "." + janeMAC
+
+	for name, token := range map[string]string{
+		"empty":         "",
+		"no signature":  "dGlnZXJzCmphbmU",
+		"other key":     other.token("tigers", "jane", issued),
+		"tampered":      tampered,
+		"invalid chars": "!!!.!!!",
+	} {
+		if _, err := sh.parse(token); !errors.Is(err, ErrInvalidShareToken) {
+			t.Errorf("parse(%s) error = %v, want %v", name, err, ErrInvalidShareToken)
+		}
+	}
+}
+
+func TestNewSharer_ReusesKey(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := newSharer(dir)
+	if err != nil {
+		t.Fatalf("newSharer() error: %v", err)
+	}
+
+	second, err := newSharer(dir)
+	if err != nil {
+		t.Fatalf("newSharer() error: %v", err)
+	}
+
+	if !bytes.Equal(first.key, second.key) {
+		t.Error("newSharer() generated a new key instead of reusing the saved key")
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SubberState is the persisted state of a Subber. Game and player statistics
+// are restored by replaying the event log.
+type SubberState struct {
+	SavedAt time.Time `json:"savedAt"`
+	Events  []Event   `json:"events"`
+}
+
+// Store persists Subber state.
+type Store interface {
+	// Load returns the most recently saved state, or an empty state if nothing
+	// has been saved yet.
+	Load() (SubberState, error)
+	// Save replaces the stored state.
+	Save(state SubberState) error
+}
+
+// MemoryStore keeps state in memory only, state is lost when the process
+// exits.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state SubberState
+}
+
+// NewMemoryStore returns an empty in memory store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Load returns the last saved state.
+func (ms *MemoryStore) Load() (SubberState, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	return ms.state, nil
+}
+
+// Save replaces the stored state.
+func (ms *MemoryStore) Save(state SubberState) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	ms.state = state
+
+	return nil
+}
+
+// legacyStateFile is where the state of the only team was saved before
+// several teams could be served.
+const legacyStateFile = "subber.json"
+
+// teamStateFile returns the path of the team's state file in dir.
+func teamStateFile(dir, team string) string {
+	return filepath.Join(dir, url.PathEscape(team)+".json")
+}
+
+// migrateStateFile moves the legacy state file in dir to the team's state
+// file, unless the team already has one. Returns true if the file was moved.
+func migrateStateFile(dir, team string) (bool, error) {
+	path := teamStateFile(dir, team)
+
+	if _, err := os.Stat(path); err == nil {
+		return false, nil
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	if err := os.Rename(filepath.Join(dir, legacyStateFile), path); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to move state file: %w", err)
+	}
+
+	return true, nil
+}
+
+// FileStore persists state as JSON to a file on the local disk.
+type FileStore struct {
+	path string
+}
+
+// NewFileStore returns a store that reads and writes state to path. The parent
+// directory is created on first save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Load reads state from disk. A missing file is not an error and returns an
+// empty state.
+func (s *FileStore) Load() (SubberState, error) {
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return SubberState{}, nil
+	}
+
+	if err != nil {
+		return SubberState{}, fmt.Errorf("failed to read state file: %w", err)
+	}
+
+	var state SubberState
+	if err := json.Unmarshal(b, &state); err != nil {
+		return SubberState{}, fmt.Errorf("failed to parse state file %s: %w", s.path, err)
+	}
+
+	return state, nil
+}
+
+// Save writes state to a temporary file and renames it over the previous
+// state so a crash mid write never leaves a truncated file behind.
+func (s *FileStore) Save(state SubberState) error {
+	b, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode state: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary state file: %w", err)
+	}
+
+	// best effort clean up, a successful rename leaves nothing to remove.
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("failed to sync state file: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close state file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace state file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileStore_MissingFile(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "missing", "subber.json"))
+
+	got, err := store.Load()
+	if err != nil {
+		t.Errorf("failed to load missing state file: %v", err)
+	}
+
+	if diff := cmp.Diff(SubberState{}, got); diff != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewSubber_RestoresState(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	store := NewFileStore(filepath.Join(t.TempDir(), "subber.json"))
+	team := TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}},
+	}
+
+	before, err := NewSubber(logger, store, team)
+	if err != nil {
+		t.Fatalf("failed to create subber: %v", err)
+	}
+
+	before.StartGame()
+	before.PlayerSubOn("jane")
+	before.PlayerSet("john", 2, 3*time.Minute)
+
+	after, err := NewSubber(logger, store, team)
+	if err != nil {
+		t.Fatalf("failed to restore subber: %v", err)
+	}
+
+	if got := after.CurrentGame().State(); got != GameStateInProgress {
+		t.Errorf("restored game state = %s, want %s", got, GameStateInProgress)
+	}
+
+	want := before.tally.players
+	got := after.tally.players
+
+	// time.Time monotonic readings are not persisted.
+	opt := cmp.Comparer(func(a, b time.Time) bool { return a.Equal(b) })
+	if diff := cmp.Diff(want, got, opt); diff != "" {
+		t.Errorf("restored players mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestMigrateStateFile(t *testing.T) {
+	tests := []struct {
+		name   string
+		legacy string
+		team   string
+		moved  bool
+		// want is the team's state file afterwards.
+		want string
+	}{
+		{
+			name:   "moved",
+			legacy: "legacy",
+			moved:  true,
+			want:   "legacy",
+		},
+		{
+			name:   "team file kept",
+			legacy: "legacy",
+			team:   "team",
+			want:   "team",
+		},
+		{
+			name: "nothing to move",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := teamStateFile(dir, "under 9s")
+
+			for file, content := range map[string]string{filepath.Join(dir, legacyStateFile): tc.legacy, path: tc.team} {
+				if content == "" {
+					continue
+				}
+
+				if err := os.WriteFile(file, []byte(content), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			moved, err := migrateStateFile(dir, "under 9s")
+			if err != nil {
+				t.Fatalf("migrateStateFile() error = %v", err)
+			}
+
+			if moved != tc.moved {
+				t.Errorf("migrateStateFile() = %t, want %t", moved, tc.moved)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				t.Fatal(err)
+			}
+
+			if string(got) != tc.want {
+				t.Errorf("team state file = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
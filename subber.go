@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -15,7 +19,11 @@ type Period struct {
 type Game struct {
 	StartTime time.Time
 	EndTime   time.Time
-	periods   []Period
+	// Score and OpponentScore are the points scored by the team and the
+	// opposition.
+	Score         int
+	OpponentScore int
+	periods       []Period
 }
 
 type GameState string
@@ -65,196 +73,683 @@ func (g Game) CurrentPeriod() Period {
 
 type Player struct {
 	// Name of the player, expected to be unique.
-	Name         string
-	Number       int
-	PlayCount    int
-	PlayDuration time.Duration
-	Playing      bool
-	PlayStarted  time.Time
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+	// PreferredPosition is where the player usually plays, listed first when
+	// choosing their position.
+	PreferredPosition string `json:"position,omitempty"`
+	// Availability of the player this game, empty when available.
+	Availability Availability  `json:"availability,omitempty"`
+	PlayCount    int           `json:"-"`
+	PlayDuration time.Duration `json:"-"`
+	Playing      bool          `json:"-"`
+	PlayStarted  time.Time     `json:"-"`
+	// RestStarted is when the player was last subbed off, zero if they haven't
+	// played this game.
+	RestStarted time.Time `json:"-"`
+	// Points and Assists are credited by scores this game.
+	Points  int `json:"-"`
+	Assists int `json:"-"`
+	// Position is the position the player is filling while playing, empty if
+	// unknown.
+	Position string `json:"-"`
+	// PositionDurations is the time played in each position this game.
+	PositionDurations map[string]time.Duration `json:"-"`
+}
+
+// Availability is whether a player can be subbed on this game.
+type Availability string
+
+const (
+	AvailabilityAvailable Availability = "available"
+	AvailabilityAbsent    Availability = "absent"
+	// AvailabilityInjured players are subbed off and can't return this game.
+	AvailabilityInjured Availability = "injured"
+	// AvailabilitySentOff players are subbed off and can't return this game.
+	AvailabilitySentOff Availability = "sent_off"
+)
+
+// Availabilities lists every availability, for choosing between them.
+var Availabilities = []Availability{
+	AvailabilityAvailable,
+	AvailabilityAbsent,
+	AvailabilityInjured,
+	AvailabilitySentOff,
+}
+
+func (a Availability) String() string {
+	if a == "" {
+		return string(AvailabilityAvailable)
+	}
+
+	return string(a)
+}
+
+// Label returns the availability for display, such as `sent off`.
+func (a Availability) Label() string {
+	return strings.ReplaceAll(a.String(), "_", " ")
+}
+
+// Available returns true when the player can be subbed on.
+func (p Player) Available() bool {
+	return p.Availability == "" || p.Availability == AvailabilityAvailable
 }
 
 // Subber manages Player stastitcs.
 type Subber struct {
 	logger *slog.Logger
-	Game   Game
-
-	mu      sync.RWMutex
-	players map[string]Player // map[name]Player
+	store  Store
+	name   string
+	// onField is the number of positions on the field, zero if unknown.
+	onField int
+	// positions on the field, such as GK, empty if unknown.
+	positions []string
+	format    GameFormat
+	// subInterval is the time between rolling subs, zero for no reminders.
+	subInterval time.Duration
+
+	mu     sync.RWMutex
+	roster []Player
+	events []Event
+	tally  *tally
+	// subscribers are notified after every change.
+	subscribers map[chan struct{}]struct{}
 }
 
 // General
 
-// NewSubber returns a subber ready for the game.
-func NewSubber(logger *slog.Logger, players []Player) *Subber {
-	ps := make(map[string]Player)
+// NewSubber returns a subber for the team ready for the game, replaying any
+// events previously saved to the store.
+func NewSubber(logger *slog.Logger, store Store, team TeamConfig) (*Subber, error) {
+	state, err := store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load subber state: %w", err)
+	}
+
+	t, errs := replay(team.Players, state.Events)
+	for _, err := range errs {
+		logger.Warn("skipping saved event", "error", err)
+	}
+
+	if len(state.Events) > 0 {
+		logger.Info("restored saved state",
+			"saved_at", state.SavedAt,
+			"events", len(state.Events),
+			"game_state", t.game.State().String(),
+		)
+	}
 
-	for _, player := range players {
-		ps[player.Name] = player
+	// every position is filled when the number on the field isn't known.
+	onField := team.OnField
+	if onField == 0 {
+		onField = len(team.Positions)
 	}
 
 	return &Subber{
-		logger:  logger,
-		mu:      sync.RWMutex{},
-		players: ps,
+		logger:    logger,
+		store:     store,
+		name:      team.Name,
+		onField:   onField,
+		positions: append([]string{}, team.Positions...),
+		format:    team.Format,
+
+		subInterval: time.Duration(team.SubInterval),
+		mu:          sync.RWMutex{},
+		roster:      append([]Player{}, team.Players...),
+		events:      state.Events,
+		tally:       t,
+
+		subscribers: make(map[chan struct{}]struct{}),
+	}, nil
+}
+
+// Name returns the name of the team.
+func (s *Subber) Name() string {
+	return s.name
+}
+
+// record applies the event and appends it to the event log, saving the log
+// to the store. Callers must hold s.mu.
+func (s *Subber) record(e Event) error {
+	if err := s.tally.apply(e); err != nil {
+		return err
 	}
+
+	s.events = append(s.events, e)
+	s.save()
+	s.notify()
+
+	return nil
 }
 
-// StartGame starts the game timer and resets all player statistics.
-func (s *Subber) StartGame() {
-	p := Period{
-		StartTime: time.Now(),
-		EndTime:   time.Time{},
+// save writes the event log to the store. Callers must hold s.mu.
+// Failures are logged rather than returned so a full disk never interrupts
+// a game.
+func (s *Subber) save() {
+	state := SubberState{
+		SavedAt: time.Now(),
+		Events:  s.events,
+	}
+
+	if err := s.store.Save(state); err != nil {
+		s.logger.Error("failed to save state", "error", err)
 	}
-	s.Game.periods = append(s.Game.periods, p)
+}
+
+// CurrentGame returns a copy of the game safe to read while the game continues.
+func (s *Subber) CurrentGame() Game {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	g := s.tally.game
+	g.periods = append([]Period{}, s.tally.game.periods...)
+
+	return g
+}
+
+// History returns the archived games, oldest first.
+func (s *Subber) History() []GameRecord {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]GameRecord{}, s.tally.history...)
+}
+
+// Season returns player statistics totalled across all archived games.
+func (s *Subber) Season() []PlayerSeason {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return seasonStats(s.tally.history)
+}
+
+// Events returns a copy of the event log, oldest first.
+func (s *Subber) Events() []Event {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]Event{}, s.events...)
+}
 
-	s.Game.StartTime = time.Now()
-	s.Game.EndTime = time.Time{}
+// PlayersAt returns player statistics as they were at the provided time, by
+// replaying the event log up until then. Undone actions are treated as never
+// having happened.
+func (s *Subber) PlayersAt(at time.Time) []Player {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	applied, _ := resolve(s.events)
+	idx := sort.Search(len(applied), func(i int) bool {
+		return applied[i].Time.After(at)
+	})
+
+	// errors were already reported when the events were first applied.
+	t, _ := replay(s.roster, applied[:idx])
+
+	return t.list(at)
+}
 
-	for _, p := range s.players {
-		s.PlayerReset(p.Name)
+// UndoRedo returns the actions that Undo and Redo would revert or restore, nil
+// if there are none.
+func (s *Subber) UndoRedo() (*Event, *Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var undo, redo *Event
+
+	applied, undone := resolve(s.events)
+	if len(applied) > 0 {
+		undo = &applied[len(applied)-1]
 	}
+
+	if len(undone) > 0 {
+		redo = &undone[len(undone)-1]
+	}
+
+	return undo, redo
 }
 
-// PauseGame pauses the game clock and subs off all players.
-func (s *Subber) PauseGame() {
-	idx := len(s.Game.periods) - 1
-	s.Game.periods[idx].EndTime = time.Now()
+// Undo reverts the most recent action, restoring game and player statistics
+// exactly as they were before it.
+func (s *Subber) Undo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for _, p := range s.players {
-		s.PlayerSubOff(p.Name)
+	if applied, _ := resolve(s.events); len(applied) == 0 {
+		return ErrNothingToUndo
 	}
+
+	return s.rewind(Event{Time: time.Now(), Type: EventUndo})
 }
 
-// ResumeGame resumes the game.
-func (s *Subber) ResumeGame() {
-	p := Period{
-		StartTime: time.Now(),
-		EndTime:   time.Time{},
+// Redo restores the most recently undone action with its original timing.
+func (s *Subber) Redo() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, undone := resolve(s.events); len(undone) == 0 {
+		return ErrNothingToRedo
 	}
-	s.Game.periods = append(s.Game.periods, p)
 
-	for _, p := range s.players {
-		s.PlayerSubOff(p.Name)
+	return s.rewind(Event{Time: time.Now(), Type: EventRedo})
+}
+
+// rewind appends an undo or redo event and rebuilds the tally by replaying the
+// event log. Callers must hold s.mu.
+func (s *Subber) rewind(e Event) error {
+	events := append(s.events, e)
+
+	t, errs := replay(s.roster, events)
+	for _, err := range errs {
+		s.logger.Warn("skipping event", "error", err)
 	}
+
+	s.events = events
+	s.tally = t
+	s.save()
+	s.notify()
+
+	return nil
 }
 
-// EndGame stops the game clock and subs off all players. It does not reset statistics.
-func (s *Subber) EndGame() {
-	idx := len(s.Game.periods) - 1
-	s.Game.periods[idx].EndTime = time.Now()
-	s.Game.EndTime = time.Now()
+// SetRoster replaces the players the event log is replayed on, such as when
+// the config file changes, applying new and changed players without
+// disturbing game statistics. Players missing from roster are kept so their
+// statistics aren't lost, and players added by the event log are left to it.
+// Returns ErrRosterConflict, leaving the roster unchanged, when saved events
+// can no longer be applied, such as a number now worn by two players.
+func (s *Subber) SetRoster(roster []Player) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	added := make(map[string]bool)
 
-	for _, p := range s.players {
-		s.PlayerSubOff(p.Name)
+	for _, e := range s.events {
+		if e.Type == EventPlayerAdded {
+			added[e.Player] = true
+		}
+	}
+
+	merged := make([]Player, 0, len(roster)+len(s.roster))
+
+	for _, p := range roster {
+		if !added[p.Name] {
+			merged = append(merged, p)
+		}
 	}
+
+	for _, p := range s.roster {
+		if !slices.ContainsFunc(merged, func(m Player) bool { return m.Name == p.Name }) {
+			merged = append(merged, p)
+		}
+	}
+
+	if slices.EqualFunc(merged, s.roster, func(a, b Player) bool {
+		return a.Name == b.Name && sameRosterDetails(a, b)
+	}) {
+		return nil
+	}
+
+	_, before := replay(s.roster, s.events)
+
+	t, after := replay(merged, s.events)
+	if len(after) > len(before) {
+		return fmt.Errorf("%w: %w", ErrRosterConflict, errors.Join(after...))
+	}
+
+	s.roster = merged
+	s.tally = t
+	s.notify()
+
+	return nil
 }
 
-// ResetGame stops the game clock and resets all player statistics.
-func (s *Subber) ResetGame() {
-	s.Game.StartTime = time.Time{}
-	s.Game.EndTime = time.Time{}
-	s.Game.periods = []Period{}
+// Subscribe returns a channel that receives a value after every change to the
+// game or players, and a function to stop receiving. Notifications are
+// coalesced, a slow subscriber receives one value for many changes.
+func (s *Subber) Subscribe() (<-chan struct{}, func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	for _, p := range s.players {
-		s.PlayerReset(p.Name)
+	ch := make(chan struct{}, 1)
+	s.subscribers[ch] = struct{}{}
+
+	return ch, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		delete(s.subscribers, ch)
 	}
 }
 
+// notify subscribers of a change without blocking. Callers must hold s.mu.
+func (s *Subber) notify() {
+	for ch := range s.subscribers {
+		select {
+		case ch <- struct{}{}:
+		default: // a notification is already pending.
+		}
+	}
+}
+
+// StartGame starts the game timer and resets all player statistics.
+func (s *Subber) StartGame() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventGameStarted})
+}
+
+// PauseGame pauses the game clock and subs off all players.
+func (s *Subber) PauseGame() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventGamePaused})
+}
+
+// ResumeGame resumes the game.
+func (s *Subber) ResumeGame() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventGameResumed})
+}
+
+// EndGame stops the game clock, subs off all players and archives the game. It
+// does not reset statistics.
+func (s *Subber) EndGame() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventGameEnded})
+}
+
+// ResetGame stops the game clock and resets all player statistics. A game that
+// was not ended is archived first.
+func (s *Subber) ResetGame() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventGameReset})
+}
+
 // ListPlayers returns all player statistics.
 func (s *Subber) ListPlayers() []Player {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	players := make([]Player, 0, len(s.players))
-	for _, p := range s.players {
-		if p.Playing {
-			d := time.Since(p.PlayStarted)
-			p.PlayDuration = time.Duration(p.PlayDuration.Nanoseconds() + d.Nanoseconds())
-		}
+	return s.tally.list(time.Now())
+}
 
-		players = append(players, p)
+// FieldCapacity returns the number of players on the field and the number of
+// positions, zero if unknown.
+func (s *Subber) FieldCapacity() (int, int) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.tally.onField(), s.onField
+}
+
+// Positions returns the positions on the field, empty if unknown.
+func (s *Subber) Positions() []string {
+	return append([]string{}, s.positions...)
+}
+
+// Suggest returns substitutions that would even out play duration while a
+// game is in progress.
+func (s *Subber) Suggest() []Swap {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.tally.game.State() != GameStateInProgress {
+		return nil
 	}
 
-	sort.Slice(players, func(i, j int) bool {
-		return players[i].Name < players[j].Name
-	})
+	return suggestSwaps(s.tally.list(time.Now()), s.onField)
+}
+
+// SubDue returns when the next rolling sub is due, the sub interval after the
+// later of the current period starting and the most recent sub. Zero when
+// there is no sub interval or the game is not in progress.
+func (s *Subber) SubDue() time.Time {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.subInterval <= 0 || s.tally.game.State() != GameStateInProgress {
+		return time.Time{}
+	}
+
+	from := s.tally.game.CurrentPeriod().StartTime
+	if s.tally.lastSub.After(from) {
+		from = s.tally.lastSub
+	}
 
-	return players
+	return from.Add(s.subInterval)
+}
+
+// RollingSub returns the swap to make when a rolling sub is due, see
+// rollingSwap.
+func (s *Subber) RollingSub() Swap {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return rollingSwap(s.tally.list(time.Now()))
 }
 
 // Per Player
 
 // PlayerReset zero's a players game time and play count.
-func (s *Subber) PlayerReset(name string) {
+func (s *Subber) PlayerReset(name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	p, ok := s.players[name]
-	if !ok {
-		s.logger.Warn("attempt to reset non-existent player", "player", name)
+	return s.record(Event{Time: time.Now(), Type: EventPlayerReset, Player: name})
+}
 
-		return
-	}
+// PlayerSet updates a players game time and play count to the provided values.
+func (s *Subber) PlayerSet(name string, playCount int, playDuration time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{
+		Time:         time.Now(),
+		Type:         EventPlayerSet,
+		Player:       name,
+		PlayCount:    playCount,
+		PlayDuration: playDuration,
+	})
+}
 
-	p.PlayCount = 0
-	p.PlayDuration = 0
-	p.Playing = false
-	p.PlayStarted = time.Time{}
-	s.players[name] = p
+// PlayerSubOn a player, increment their play count and starting or resuming play duration timer.
+// Returns ErrFieldFull if every position on the field is already taken.
+func (s *Subber) PlayerSubOn(name string) error {
+	return s.PlayerSubOnPosition(name, "")
 }
 
-// PlayerSet updates a players game time and play count to the provided values.
-func (s *Subber) PlayerSet(name string, playCount int, playDuration time.Duration) {
+// PlayerSubOnPosition subs on a player to fill the position, empty if unknown.
+// Returns ErrPositionFilled if another player is already playing there.
+func (s *Subber) PlayerSubOnPosition(name, position string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	p, ok := s.players[name]
-	if !ok {
-		return
+	if p, ok := s.tally.players[name]; ok && !p.Playing {
+		if on := s.tally.onField(); s.onField > 0 && on >= s.onField {
+			return ErrFieldFull
+		}
 	}
 
-	p.PlayCount = playCount
-	p.PlayDuration = playDuration
-	s.players[name] = p
+	if position != "" {
+		if !slices.Contains(s.positions, position) {
+			return fmt.Errorf("%w: %s", ErrInvalidPosition, position)
+		}
+
+		for _, p := range s.tally.players {
+			if p.Playing && p.Position == position && p.Name != name {
+				return fmt.Errorf("%w: %s plays %s", ErrPositionFilled, p.Name, position)
+			}
+		}
+	}
+
+	return s.record(Event{Time: time.Now(), Type: EventPlayerSubOn, Player: name, Position: position})
 }
 
-// PlayerSubOn a player, increment their play count and starting or resuming play duration timer.
-func (s *Subber) PlayerSubOn(name string) {
+// PlayerSubOff a player, pausing play duration timer.
+func (s *Subber) PlayerSubOff(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventPlayerSubOff, Player: name})
+}
+
+// Score adds points for the team, crediting the optional scorer and assist.
+func (s *Subber) Score(scorer, assist string, points int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{
+		Time:   time.Now(),
+		Type:   EventScore,
+		Player: scorer,
+		Points: points,
+		Assist: assist,
+	})
+}
+
+// OpponentScore adds points for the opposition.
+func (s *Subber) OpponentScore(points int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventOpponentScore, Points: points})
+}
+
+// AddPlayer adds a player to the roster, saved with the event log.
+func (s *Subber) AddPlayer(name string, number int) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	p, ok := s.players[name]
-	if !ok {
-		s.logger.Warn("attempt to sub on non-existent player", "player", name)
+	return s.record(Event{Time: time.Now(), Type: EventPlayerAdded, Player: name, Number: number})
+}
+
+// UpdatePlayer renames and renumbers a player, keeping their statistics.
+func (s *Subber) UpdatePlayer(name, newName string, number int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-		return
+	return s.record(Event{
+		Time:    time.Now(),
+		Type:    EventPlayerUpdated,
+		Player:  name,
+		Number:  number,
+		NewName: newName,
+	})
+}
+
+// PlayerName returns the current name of the player who was named name at
+// since, following later renames. Empty if they have since been removed.
+func (s *Subber) PlayerName(name string, since time.Time) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	applied, _ := resolve(s.events)
+	for _, e := range applied {
+		if e.Player != name || e.Time.Before(since) {
+			continue
+		}
+
+		switch e.Type {
+		case EventPlayerUpdated:
+			if e.NewName != "" {
+				name = e.NewName
+			}
+		case EventPlayerRemoved:
+			return ""
+		}
 	}
 
-	p.Playing = true
-	p.PlayCount++
-	p.PlayStarted = time.Now()
-	s.players[name] = p
+	return name
 }
 
-// PlayerSubOff a player, pausing play duration timer.
-func (s *Subber) PlayerSubOff(name string) {
+// RemovePlayer removes a player from the roster. Returns ErrPlayerOnField if
+// they are playing.
+func (s *Subber) RemovePlayer(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{Time: time.Now(), Type: EventPlayerRemoved, Player: name})
+}
+
+// ImportPlayers merges imported players into the roster, see mergeRoster, as
+// a single action undone in one step. Players that can't be added or updated
+// are returned as problems.
+func (s *Subber) ImportPlayers(imported []Player) ImportResult {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	p, ok := s.players[name]
-	if !ok {
-		s.logger.Warn("attempt to sub off non-existent player", "player", name)
+	now := time.Now()
+	merged, result := mergeRoster(s.tally.list(now), imported, s.positions)
 
-		return
+	var players []Player
+
+	for _, p := range merged {
+		if slices.Contains(result.Added, p.Name) || slices.Contains(result.Updated, p.Name) {
+			players = append(players, newPlayer(p))
+		}
 	}
 
-	// calculate time playing
-	if !p.PlayStarted.IsZero() {
-		d := time.Since(p.PlayStarted)
-		p.PlayDuration = time.Duration(p.PlayDuration.Nanoseconds() + d.Nanoseconds())
+	if len(players) > 0 {
+		if err := s.record(Event{Time: now, Type: EventPlayersImported, Players: players}); err != nil {
+			result.Problems = append(result.Problems, err)
+			result.Added, result.Updated = nil, nil
+		}
+	}
+
+	s.logger.Info("imported players", "result", result.Summary())
+
+	return result
+}
+
+// PlayerAvailability sets whether the player can play this game, subbing them
+// off if they are no longer available. Availability is reset with the game.
+func (s *Subber) PlayerAvailability(name string, availability Availability) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.record(Event{
+		Time:         time.Now(),
+		Type:         EventPlayerAvailability,
+		Player:       name,
+		Availability: availability,
+	})
+}
+
+// Swap subs players off and on as a single action with one shared timestamp,
+// so no time is lost between them. Returns ErrFieldFull if the swap would
+// leave more players on the field than there are positions.
+func (s *Subber) Swap(off, on []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.onField > 0 {
+		n := s.tally.onField()
+
+		for _, name := range off {
+			if s.tally.players[name].Playing {
+				n--
+			}
+		}
+
+		for _, name := range on {
+			if !s.tally.players[name].Playing {
+				n++
+			}
+		}
+
+		if n > s.onField {
+			return ErrFieldFull
+		}
 	}
 
-	p.Playing = false
-	p.PlayStarted = time.Time{}
-	s.players[name] = p
+	return s.record(Event{Time: time.Now(), Type: EventSwap, Off: off, On: on})
 }
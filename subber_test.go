@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestSubber(t *testing.T, team TeamConfig) *Subber {
+	t.Helper()
+
+	s, err := NewSubber(slog.New(slog.NewTextHandler(io.Discard, nil)), NewMemoryStore(), team)
+	if err != nil {
+		t.Fatalf("failed to create subber: %v", err)
+	}
+
+	return s
+}
+
+func TestSubber_PlayerSubOn_FieldFull(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane"}, {Name: "john"}, {Name: "mary"}},
+		OnField: 2,
+	})
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	for _, name := range []string{"jane", "john"} {
+		if err := s.PlayerSubOn(name); err != nil {
+			t.Fatalf("PlayerSubOn(%q) error: %v", name, err)
+		}
+	}
+
+	if err := s.PlayerSubOn("mary"); !errors.Is(err, ErrFieldFull) {
+		t.Errorf("PlayerSubOn(mary) error = %v, want %v", err, ErrFieldFull)
+	}
+
+	if on, capacity := s.FieldCapacity(); on != 2 || capacity != 2 {
+		t.Errorf("FieldCapacity() = %d/%d, want 2/2", on, capacity)
+	}
+}
+
+func TestSubber_PlayerSubOnPosition(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:      "tigers",
+		Players:   []Player{{Name: "jane"}, {Name: "john"}, {Name: "mary"}},
+		Positions: []string{"GK", "C"},
+	})
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	if err := s.PlayerSubOnPosition("jane", "GK"); err != nil {
+		t.Fatalf("PlayerSubOnPosition(jane, GK) error: %v", err)
+	}
+
+	if err := s.PlayerSubOnPosition("john", "GK"); !errors.Is(err, ErrPositionFilled) {
+		t.Errorf("PlayerSubOnPosition(john, GK) error = %v, want %v", err, ErrPositionFilled)
+	}
+
+	if err := s.PlayerSubOnPosition("john", "WD"); !errors.Is(err, ErrInvalidPosition) {
+		t.Errorf("PlayerSubOnPosition(john, WD) error = %v, want %v", err, ErrInvalidPosition)
+	}
+
+	if err := s.PlayerSubOnPosition("john", "C"); err != nil {
+		t.Fatalf("PlayerSubOnPosition(john, C) error: %v", err)
+	}
+
+	// the number on the field defaults to the number of positions.
+	if err := s.PlayerSubOn("mary"); !errors.Is(err, ErrFieldFull) {
+		t.Errorf("PlayerSubOn(mary) error = %v, want %v", err, ErrFieldFull)
+	}
+}
+
+func TestSubber_Swap(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane"}, {Name: "john"}, {Name: "mary"}},
+		OnField: 1,
+	})
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	if err := s.PlayerSubOn("jane"); err != nil {
+		t.Fatalf("PlayerSubOn(jane) error: %v", err)
+	}
+
+	if err := s.Swap([]string{"jane"}, []string{"john", "mary"}); !errors.Is(err, ErrFieldFull) {
+		t.Errorf("Swap() onto a full field error = %v, want %v", err, ErrFieldFull)
+	}
+
+	if err := s.Swap([]string{"jane"}, []string{"john"}); err != nil {
+		t.Fatalf("Swap() error: %v", err)
+	}
+
+	events := s.Events()
+	swap := events[len(events)-1]
+
+	players := s.tally.players
+	if players["jane"].Playing || !players["john"].Playing {
+		t.Errorf("Swap() jane playing = %t, john playing = %t, want false, true",
+			players["jane"].Playing, players["john"].Playing)
+	}
+
+	if !players["john"].PlayStarted.Equal(swap.Time) {
+		t.Errorf("Swap() john started at %s, want swap time %s", players["john"].PlayStarted, swap.Time)
+	}
+}
+
+func TestSubber_SubDue(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:        "tigers",
+		Players:     []Player{{Name: "jane"}, {Name: "john"}},
+		SubInterval: Duration(4 * time.Minute),
+	})
+
+	if got := s.SubDue(); !got.IsZero() {
+		t.Errorf("SubDue() before game = %v, want zero", got)
+	}
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	start := s.CurrentGame().CurrentPeriod().StartTime
+	if got, want := s.SubDue(), start.Add(4*time.Minute); !got.Equal(want) {
+		t.Errorf("SubDue() = %v, want %v", got, want)
+	}
+
+	if err := s.PlayerSubOn("jane"); err != nil {
+		t.Fatalf("PlayerSubOn(jane) error: %v", err)
+	}
+
+	// the interval restarts from the most recent sub.
+	if got := s.SubDue(); !got.After(start.Add(4 * time.Minute)) {
+		t.Errorf("SubDue() = %v, want after %v", got, start.Add(4*time.Minute))
+	}
+
+	if diff := cmp.Diff(Swap{Off: "jane", On: "john"}, s.RollingSub()); diff != "" {
+		t.Errorf("RollingSub() mismatch (-want +got):\n%s", diff)
+	}
+
+	if err := s.PauseGame(); err != nil {
+		t.Fatalf("PauseGame() error: %v", err)
+	}
+
+	if got := s.SubDue(); !got.IsZero() {
+		t.Errorf("SubDue() while paused = %v, want zero", got)
+	}
+}
+
+func TestSubber_SetRoster(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:    "tigers",
+		Players: []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}},
+	})
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error: %v", err)
+	}
+
+	if err := s.PlayerSubOn("john"); err != nil {
+		t.Fatalf("PlayerSubOn(john) error: %v", err)
+	}
+
+	if err := s.AddPlayer("mary", 3); err != nil {
+		t.Fatalf("AddPlayer(mary) error: %v", err)
+	}
+
+	// john is missing, mary was added by the event log and bob is new.
+	if err := s.SetRoster([]Player{
+		{Name: "jane", Number: 7, PreferredPosition: "GK"},
+		{Name: "mary", Number: 9},
+		{Name: "bob", Number: 4},
+	}); err != nil {
+		t.Fatalf("SetRoster() error: %v", err)
+	}
+
+	got := make(map[string]Player)
+	for _, p := range s.ListPlayers() {
+		got[p.Name] = p
+	}
+
+	if p := got["jane"]; p.Number != 7 || p.PreferredPosition != "GK" {
+		t.Errorf("jane = %d %q, want 7 GK", p.Number, p.PreferredPosition)
+	}
+
+	if p := got["john"]; !p.Playing || p.PlayCount != 1 {
+		t.Errorf("john Playing = %t, PlayCount = %d, want playing once", p.Playing, p.PlayCount)
+	}
+
+	if p := got["mary"]; p.Number != 3 {
+		t.Errorf("mary Number = %d, want 3 from the event log", p.Number)
+	}
+
+	if _, ok := got["bob"]; !ok {
+		t.Error("bob was not added")
+	}
+
+	if s.CurrentGame().State() != GameStateInProgress {
+		t.Errorf("State() = %s, want %s", s.CurrentGame().State(), GameStateInProgress)
+	}
+
+	// mary already wears 3.
+	if err := s.SetRoster([]Player{{Name: "jane", Number: 3}}); !errors.Is(err, ErrRosterConflict) {
+		t.Errorf("SetRoster() error = %v, want %v", err, ErrRosterConflict)
+	}
+
+	for _, p := range s.ListPlayers() {
+		if p.Name == "jane" && p.Number != 7 {
+			t.Errorf("jane Number = %d after rejected roster, want 7", p.Number)
+		}
+	}
+}
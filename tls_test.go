@@ -0,0 +1,269 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestGenerateSelfSigned(t *testing.T) {
+	now := time.Date(2025, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	certPEM, keyPEM, err := generateSelfSigned(now)
+	if err != nil {
+		t.Fatalf("generateSelfSigned() error = %v", err)
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatalf("tls.X509KeyPair() error = %v", err)
+	}
+
+	leaf := cert.Leaf
+
+	if !slices.Contains(leaf.DNSNames, "localhost") {
+		t.Errorf("DNSNames = %v, want localhost", leaf.DNSNames)
+	}
+
+	if !slices.ContainsFunc(leaf.IPAddresses, net.IP.IsLoopback) {
+		t.Errorf("IPAddresses = %v, want a loopback address", leaf.IPAddresses)
+	}
+
+	if !leaf.NotBefore.Before(now) || !leaf.NotAfter.Equal(now.Add(selfSignedValidFor)) {
+		t.Errorf("valid %v to %v, want from before %v for %v", leaf.NotBefore, leaf.NotAfter, now, selfSignedValidFor)
+	}
+
+	if !slices.Contains(leaf.ExtKeyUsage, x509.ExtKeyUsageServerAuth) {
+		t.Errorf("ExtKeyUsage = %v, want server auth", leaf.ExtKeyUsage)
+	}
+}
+
+func TestLoadOrCreateSelfSigned(t *testing.T) {
+	dir := t.TempDir()
+
+	first, err := loadOrCreateSelfSigned(dir)
+	if err != nil {
+		t.Fatalf("loadOrCreateSelfSigned() error = %v", err)
+	}
+
+	// the saved certificate is reused so phones only trust it once.
+	second, err := loadOrCreateSelfSigned(dir)
+	if err != nil {
+		t.Fatalf("loadOrCreateSelfSigned() error = %v", err)
+	}
+
+	if !first.Leaf.Equal(second.Leaf) {
+		t.Error("loadOrCreateSelfSigned() generated a new certificate, want the saved one")
+	}
+
+	if info, err := os.Stat(filepath.Join(dir, selfSignedKeyFile)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("key file = %v, %v, want mode 0600", info, err)
+	}
+
+	// an expired certificate is replaced.
+	certPEM, keyPEM, err := generateSelfSigned(time.Now().Add(-2 * selfSignedValidFor))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, selfSignedCertFile), certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, selfSignedKeyFile), keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	renewed, err := loadOrCreateSelfSigned(dir)
+	if err != nil {
+		t.Fatalf("loadOrCreateSelfSigned() error = %v", err)
+	}
+
+	if !time.Now().Before(renewed.Leaf.NotAfter) {
+		t.Errorf("NotAfter = %v, want a renewed certificate", renewed.Leaf.NotAfter)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	certPEM, keyPEM, err := generateSelfSigned(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		cfg     ServerConfig
+		enabled bool
+		err     error
+	}{
+		{
+			name: "disabled",
+		},
+		{
+			name:    "certificate files",
+			cfg:     ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile},
+			enabled: true,
+		},
+		{
+			name:    "self-signed",
+			cfg:     ServerConfig{TLSSelfSigned: true},
+			enabled: true,
+		},
+		{
+			name:    "certificate files before self-signed",
+			cfg:     ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile, TLSSelfSigned: true},
+			enabled: true,
+		},
+		{
+			name: "missing certificate file",
+			cfg:  ServerConfig{TLSCertFile: filepath.Join(dir, "missing.pem"), TLSKeyFile: keyFile},
+			err:  os.ErrNotExist,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := newTLSConfig(tc.cfg, "")
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("newTLSConfig() error = %v, want %v", err, tc.err)
+			}
+
+			if (got != nil) != tc.enabled {
+				t.Fatalf("newTLSConfig() = %v, want enabled %t", got, tc.enabled)
+			}
+
+			if got == nil {
+				return
+			}
+
+			if got.MinVersion != tls.VersionTLS12 || len(got.Certificates) != 1 {
+				t.Errorf("newTLSConfig() = min version %x, %d certificates, want TLS 1.2 and 1", got.MinVersion, len(got.Certificates))
+			}
+
+			if tc.cfg.TLSCertFile != "" && !got.Certificates[0].Leaf.Equal(files.Leaf) {
+				t.Error("newTLSConfig() didn't use the certificate file")
+			}
+		})
+	}
+}
+
+func TestWebServer_hsts(t *testing.T) {
+	dir := t.TempDir()
+
+	certPEM, keyPEM, err := generateSelfSigned(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+
+	if err := os.WriteFile(certFile, certPEM, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(keyFile, keyPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  ServerConfig
+		hsts string
+	}{
+		{
+			// serving HTTP/2 sets the http.Server's TLSConfig, which isn't TLS.
+			name: "http",
+		},
+		{
+			// browsers would refuse a renewed certificate until max-age passed.
+			name: "self-signed",
+			cfg:  ServerConfig{TLSSelfSigned: true},
+		},
+		{
+			name: "certificate files",
+			cfg:  ServerConfig{TLSCertFile: certFile, TLSKeyFile: keyFile},
+			hsts: "max-age=31536000",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tlsConfig, err := newTLSConfig(tc.cfg, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			sharer, err := newSharer("")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+			ws, err := NewWebServer(logger, tc.cfg, AuthConfig{}, tlsConfig, sharer, nil)
+			if err != nil {
+				t.Fatalf("NewWebServer() error = %v", err)
+			}
+
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := http.DefaultClient
+			url := "http://" + ln.Addr().String() + "/robots.txt"
+
+			if tlsConfig != nil {
+				roots := x509.NewCertPool()
+				roots.AddCert(tlsConfig.Certificates[0].Leaf)
+
+				client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+				url = "https://" + ln.Addr().String() + "/robots.txt"
+
+				go ws.srv.ServeTLS(ln, "", "")
+			} else {
+				go ws.srv.Serve(ln)
+			}
+
+			defer ws.srv.Close()
+
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			if got := resp.Header.Get("Strict-Transport-Security"); got != tc.hsts {
+				t.Errorf("Strict-Transport-Security = %q, want %q", got, tc.hsts)
+			}
+		})
+	}
+}
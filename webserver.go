@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"embed"
 	"errors"
 	"fmt"
@@ -10,6 +11,7 @@ import (
 	"net"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/a-h/templ"
@@ -21,6 +23,15 @@ const (
 	httpShutdownPreStopDelaySeconds = 0
 	httpShutdownTimeoutSeconds      = 0
 	defaultBackgroundTimeoutSeconds = 2
+	sseHeartbeatSeconds             = 30
+)
+
+// contextKey is the type of request context keys set by middleware.
+type contextKey int
+
+const (
+	viewerContextKey contextKey = iota
+	csrfContextKey
 )
 
 //go:embed assets
@@ -44,13 +55,18 @@ func (rr *responseRecorder) Write(b []byte) (int, error) {
 	return rr.w.Write(b)
 }
 
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.w
+}
+
 type headerWriter struct {
 	headers     map[string]string
 	w           http.ResponseWriter
 	wroteHeader bool
 }
 
-func newHeaderWriter(local bool, w http.ResponseWriter) *headerWriter {
+func newHeaderWriter(hsts bool, w http.ResponseWriter) *headerWriter {
 	headers := map[string]string{
 		"Content-Security-Policy:":          "default-src 'self'", // https://report-uri.com/home/generate
 		"X-XSS-Protection":                  "1; mode=block",
@@ -60,9 +76,10 @@ func newHeaderWriter(local bool, w http.ResponseWriter) *headerWriter {
 		"Referrer-Policy":                   "no-referrer-when-downgrade",
 	}
 
-	// TODO: local only
-	if false {
-		headers["Strict-Transport-Security"] = "max-age=31536000; includeSubDomains; preload"
+	// only meaningful, and only safe to send, when serving HTTPS. Other sites
+	// on the same domain aren't ours to upgrade, so no includeSubDomains.
+	if hsts {
+		headers["Strict-Transport-Security"] = "max-age=31536000"
 	}
 
 	return &headerWriter{
@@ -102,15 +119,40 @@ func (hr *headerWriter) Write(b []byte) (int, error) {
 	return hr.w.Write(b)
 }
 
+// Unwrap returns the underlying ResponseWriter for http.ResponseController.
+func (hr *headerWriter) Unwrap() http.ResponseWriter {
+	return hr.w
+}
+
 type WebServer struct {
-	mux    *http.ServeMux
-	srv    *http.Server
-	logger *slog.Logger
-	subber *Subber
-	assets http.FileSystem
+	mux     *http.ServeMux
+	srv     *http.Server
+	logger  *slog.Logger
+	subbers []*Subber
+	auth    *authenticator
+	sharer  *sharer
+	assets  http.FileSystem
+	// allowedOrigins may make cross-origin requests.
+	allowedOrigins []string
+	// tls is true when serving HTTPS.
+	tls bool
+	// hsts is true when serving HTTPS with certificate files. Browsers would
+	// refuse to load the site after a self-signed certificate is renewed, or
+	// when returning to HTTP.
+	hsts bool
+	// shutdown is closed when the server begins shutting down, ending
+	// long-lived event streams.
+	shutdown chan struct{}
 }
 
-func NewWebServer(logger *slog.Logger, subber *Subber) (*WebServer, error) {
+func NewWebServer(
+	logger *slog.Logger,
+	cfg ServerConfig,
+	auth AuthConfig,
+	tlsConfig *tls.Config,
+	sharer *sharer,
+	subbers []*Subber,
+) (*WebServer, error) {
 	fsys, err := fs.Sub(fsAssets, "assets")
 	if err != nil {
 		return nil, err
@@ -118,7 +160,7 @@ func NewWebServer(logger *slog.Logger, subber *Subber) (*WebServer, error) {
 
 	mux := http.NewServeMux()
 	hs := &http.Server{
-		Addr:                         ":8081",
+		Addr:                         net.JoinHostPort(cfg.Address, strconv.Itoa(cfg.Port)),
 		Handler:                      mux,
 		DisableGeneralOptionsHandler: false,
 		ReadTimeout:                  10 * time.Second,
@@ -126,6 +168,7 @@ func NewWebServer(logger *slog.Logger, subber *Subber) (*WebServer, error) {
 		WriteTimeout:                 10 * time.Second,
 		IdleTimeout:                  10 * time.Second,
 		MaxHeaderBytes:               10 >> 10,
+		TLSConfig:                    tlsConfig,
 		TLSNextProto:                 nil,
 		ConnState:                    nil,
 		// ErrorLog:                     &log.Logger{},
@@ -136,11 +179,25 @@ func NewWebServer(logger *slog.Logger, subber *Subber) (*WebServer, error) {
 	}
 
 	ws := &WebServer{
-		mux:    mux,
-		srv:    hs,
-		logger: logger,
-		subber: subber,
-		assets: http.FS(fsys),
+		mux:     mux,
+		srv:     hs,
+		logger:  logger,
+		subbers: subbers,
+		auth:    newAuthenticator(auth),
+		sharer:  sharer,
+
+		allowedOrigins: cfg.AllowedOrigins,
+		assets:         http.FS(fsys),
+		tls:            tlsConfig != nil,
+		hsts:           tlsConfig != nil && (cfg.TLSCertFile != "" || cfg.TLSKeyFile != ""),
+
+		shutdown: make(chan struct{}),
+	}
+
+	hs.RegisterOnShutdown(func() { close(ws.shutdown) })
+
+	if !ws.auth.enabled() {
+		logger.Warn("no coach PIN or passwords configured, anyone can change subs")
 	}
 
 	// attach routes to WebServer. This is a awkward compared to defining during
@@ -160,8 +217,21 @@ func (ws *WebServer) Run(ctx context.Context) error {
 		return err
 	}
 
-	// TODO: replace `[::]:8081` with http://localhost:8081 or something clickable.
-	ws.logger.Info(fmt.Sprintf("listening on: %s", ln.Addr().String()))
+	scheme := "http"
+	if ws.tls {
+		scheme = "https"
+	}
+
+	host, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		return err
+	}
+
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		host = "localhost"
+	}
+
+	ws.logger.Info(fmt.Sprintf("listening on: %s://%s", scheme, net.JoinHostPort(host, port)))
 
 	var group errgroup.Group
 
@@ -177,11 +247,27 @@ func (ws *WebServer) Run(ctx context.Context) error {
 		drainTimeoutCtx, cancel := context.WithTimeout(ctx, httpShutdownTimeoutSeconds*time.Second)
 		defer cancel()
 
-		return ws.srv.Shutdown(drainTimeoutCtx)
+		if err := ws.srv.Shutdown(drainTimeoutCtx); err != nil {
+			if drainTimeoutCtx.Err() == nil {
+				return err
+			}
+
+			// close connections that did not drain in time, such as event streams.
+			return ws.srv.Close()
+		}
+
+		return nil
 	})
 
 	group.Go(func() error {
-		err := ws.srv.Serve(ln)
+		var err error
+		if ws.tls {
+			// certificates are provided by TLSConfig.
+			err = ws.srv.ServeTLS(ln, "", "")
+		} else {
+			err = ws.srv.Serve(ln)
+		}
+
 		// http.ErrServerClosed is expected at shutdown.
 		if errors.Is(err, http.ErrServerClosed) {
 			return nil
@@ -199,6 +285,38 @@ func (ws *WebServer) respondError(status int, err error, w http.ResponseWriter,
 	ws.logger.Error("respondError()", slog.String("error", err.Error()))
 }
 
+// errorStatus returns the HTTP status code best describing a Subber error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrPlayerNotFound),
+		errors.Is(err, ErrNoGame):
+		return http.StatusNotFound
+	case errors.Is(err, ErrInvalidSwap),
+		errors.Is(err, ErrInvalidScore),
+		errors.Is(err, ErrInvalidPosition),
+		errors.Is(err, ErrInvalidAvailability),
+		errors.Is(err, ErrInvalidPlayerName),
+		errors.Is(err, ErrInvalidPlayerNumber):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrGameNotStarted),
+		errors.Is(err, ErrGameAlreadyStarted),
+		errors.Is(err, ErrGameNotInProgress),
+		errors.Is(err, ErrGameNotPaused),
+		errors.Is(err, ErrGameFinished),
+		errors.Is(err, ErrFieldFull),
+		errors.Is(err, ErrPositionFilled),
+		errors.Is(err, ErrPlayerUnavailable),
+		errors.Is(err, ErrPlayerExists),
+		errors.Is(err, ErrNumberTaken),
+		errors.Is(err, ErrPlayerOnField),
+		errors.Is(err, ErrNothingToUndo),
+		errors.Is(err, ErrNothingToRedo):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (ws *WebServer) renderTemplate(status int, t templ.Component, w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(status)
 
@@ -212,16 +330,20 @@ func (ws *WebServer) middlewareChain(next http.Handler) http.HandlerFunc {
 	return ws.securityMiddleware(
 		ws.loggingMiddleware(
 			ws.corsMiddleware(
-				func(w http.ResponseWriter, r *http.Request) {
-					next.ServeHTTP(w, r)
-				},
-			)))
+				ws.csrfMiddleware(
+					ws.authMiddleware(
+						func(w http.ResponseWriter, r *http.Request) {
+							next.ServeHTTP(w, r)
+						},
+					)))))
 }
 
 // corsMiddleware responds to OPTION requests and injects CORS headers when required.
 // See: https://bunrouter.uptrace.dev/guide/golang-cors.html
 func (ws *WebServer) corsMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Origin")
+
 		origin := r.Header.Get("Origin")
 		if origin == "" {
 			next(w, r)
@@ -229,12 +351,26 @@ func (ws *WebServer) corsMiddleware(next func(w http.ResponseWriter, r *http.Req
 			return
 		}
 
+		// browsers block responses to other origins without CORS headers. Same
+		// origin requests may include an Origin but don't need the headers.
+		if !ws.allowedOrigin(origin) {
+			if r.Method == http.MethodOptions {
+				ws.respondError(http.StatusForbidden, fmt.Errorf("origin not allowed: %q", origin), w, r)
+
+				return
+			}
+
+			next(w, r)
+
+			return
+		}
+
 		w.Header().Set("Access-Control-Allow-Origin", origin)
 		w.Header().Set("Access-Control-Allow-Credentials", "true")
 
 		if r.Method == http.MethodOptions {
 			w.Header().Set("Access-Control-Allow-Methods", "GET,PUT,POST,DELETE,HEAD")
-			w.Header().Set("Access-Control-Allow-Headers", "authorization,content-type,content-length")
+			w.Header().Set("Access-Control-Allow-Headers", "authorization,content-type,content-length,x-csrf-token")
 			w.Header().Set("Access-Control-Max-Age", "86400")
 			w.WriteHeader(http.StatusNoContent)
 
@@ -247,8 +383,7 @@ func (ws *WebServer) corsMiddleware(next func(w http.ResponseWriter, r *http.Req
 
 func (ws *WebServer) securityMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		// TODO, get local from env
-		hr := newHeaderWriter(true, w)
+		hr := newHeaderWriter(ws.hsts, w)
 		next(hr, r)
 
 		// Other handlers didn't WriteHeader(status) or Write(b).
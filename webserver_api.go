@@ -0,0 +1,578 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// setAPIRoutes registers the JSON API, a parallel route tree to the HTML
+// routes for scripts and scoreboard displays.
+func (ws *WebServer) setAPIRoutes(mux *http.ServeMux) {
+	// coach sessions for API clients.
+	mux.HandleFunc("POST /api/v1/login", ws.apiLogin)
+	mux.HandleFunc("POST /api/v1/logout", ws.apiLogout)
+
+	mux.HandleFunc("GET /api/v1/teams", ws.apiListTeams)
+
+	// game
+	mux.HandleFunc("GET /api/v1/teams/{team}/game", ws.apiGetGame)
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/start", ws.apiGameAction((*Subber).StartGame))
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/pause", ws.apiGameAction((*Subber).PauseGame))
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/resume", ws.apiGameAction((*Subber).ResumeGame))
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/end", ws.apiGameAction((*Subber).EndGame))
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/reset", ws.apiGameAction((*Subber).ResetGame))
+	mux.HandleFunc("POST /api/v1/teams/{team}/undo", ws.apiGameAction((*Subber).Undo))
+	mux.HandleFunc("POST /api/v1/teams/{team}/redo", ws.apiGameAction((*Subber).Redo))
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/score", ws.apiScore)
+	mux.HandleFunc("POST /api/v1/teams/{team}/game/opponent-score", ws.apiOpponentScore)
+
+	// history
+	mux.HandleFunc("GET /api/v1/teams/{team}/events", ws.apiListEvents)
+	mux.HandleFunc("GET /api/v1/teams/{team}/season", ws.apiGetSeason)
+
+	// players
+	mux.HandleFunc("GET /api/v1/teams/{team}/players", ws.apiListPlayers)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players", ws.apiAddPlayer)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/update", ws.apiUpdatePlayer)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/remove", ws.apiPlayerAction((*Subber).RemovePlayer))
+	mux.HandleFunc("GET /api/v1/teams/{team}/players/{name}", ws.apiGetPlayer)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/reset", ws.apiPlayerAction((*Subber).PlayerReset))
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/set", ws.apiSetPlayer)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/sub-on", ws.apiSubOn)
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/sub-off", ws.apiPlayerAction((*Subber).PlayerSubOff))
+	mux.HandleFunc("POST /api/v1/teams/{team}/players/{name}/availability", ws.apiSetAvailability)
+
+	// subs
+	mux.HandleFunc("POST /api/v1/teams/{team}/subs/swap", ws.apiSwap)
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiTeam struct {
+	Name string `json:"name"`
+}
+
+type apiPeriod struct {
+	StartTime time.Time  `json:"startTime"`
+	EndTime   *time.Time `json:"endTime"`
+}
+
+type apiGame struct {
+	State     GameState   `json:"state"`
+	StartTime *time.Time  `json:"startTime"`
+	EndTime   *time.Time  `json:"endTime"`
+	Periods   []apiPeriod `json:"periods"`
+	OnField   int         `json:"onField"`
+	Capacity  int         `json:"capacity"`
+	// Score and OpponentScore are the points of each side.
+	Score         int `json:"score"`
+	OpponentScore int `json:"opponentScore"`
+	// PeriodEnd and BreakEnd are when the current period or break is over,
+	// null when untimed.
+	PeriodEnd *time.Time `json:"periodEnd"`
+	BreakEnd  *time.Time `json:"breakEnd"`
+	// SubDue is when the next rolling sub is due, null without a sub interval.
+	SubDue *time.Time `json:"subDue"`
+}
+
+type apiPlayer struct {
+	Name         string     `json:"name"`
+	Number       int        `json:"number"`
+	PlayCount    int        `json:"playCount"`
+	PlayDuration string     `json:"playDuration"`
+	PlaySeconds  float64    `json:"playSeconds"`
+	Playing      bool       `json:"playing"`
+	PlayStarted  *time.Time `json:"playStarted"`
+	Points       int        `json:"points"`
+	Assists      int        `json:"assists"`
+	// Position is filled while playing, empty if unknown.
+	Position        string             `json:"position"`
+	PositionSeconds map[string]float64 `json:"positionSeconds"`
+	Availability    Availability       `json:"availability"`
+}
+
+type apiPlayerSeason struct {
+	Name         string  `json:"name"`
+	Number       int     `json:"number"`
+	Games        int     `json:"games"`
+	PlayCount    int     `json:"playCount"`
+	PlayDuration string  `json:"playDuration"`
+	PlaySeconds  float64 `json:"playSeconds"`
+	Share        float64 `json:"share"`
+	Points       int     `json:"points"`
+	Assists      int     `json:"assists"`
+
+	PositionSeconds map[string]float64 `json:"positionSeconds"`
+}
+
+type apiRosterRequest struct {
+	// Name of the player, empty to keep their name when updating.
+	Name   string `json:"name"`
+	Number int    `json:"number"`
+}
+
+type apiSetPlayerRequest struct {
+	PlayCount int `json:"playCount"`
+	// PlayDuration in Go duration format, e.g. `12m30s`.
+	PlayDuration string `json:"playDuration"`
+}
+
+type apiSubOnRequest struct {
+	// Position to fill, optional.
+	Position string `json:"position"`
+}
+
+type apiAvailabilityRequest struct {
+	// Availability is one of `available`, `absent`, `injured` or `sent_off`.
+	Availability Availability `json:"availability"`
+}
+
+type apiScoreRequest struct {
+	// Player and Assist are optional, and not allowed for opponent scores.
+	Player string `json:"player"`
+	Assist string `json:"assist"`
+	// Points defaults to 1 when omitted.
+	Points int `json:"points"`
+}
+
+type apiSwapRequest struct {
+	Off []string `json:"off"`
+	On  []string `json:"on"`
+}
+
+// optionalTime returns nil for the zero time so it is encoded as null.
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func newAPIGame(s *Subber) apiGame {
+	g := s.CurrentGame()
+	onField, capacity := s.FieldCapacity()
+
+	periods := make([]apiPeriod, 0, len(g.periods))
+	for _, p := range g.periods {
+		periods = append(periods, apiPeriod{StartTime: p.StartTime, EndTime: optionalTime(p.EndTime)})
+	}
+
+	return apiGame{
+		State:         g.State(),
+		StartTime:     optionalTime(g.StartTime),
+		EndTime:       optionalTime(g.EndTime),
+		Periods:       periods,
+		OnField:       onField,
+		Capacity:      capacity,
+		Score:         g.Score,
+		OpponentScore: g.OpponentScore,
+		PeriodEnd:     optionalTime(s.Format().PeriodEnd(g)),
+		BreakEnd:      optionalTime(s.Format().BreakEnd(g)),
+		SubDue:        optionalTime(s.SubDue()),
+	}
+}
+
+func newAPIPlayer(p Player) apiPlayer {
+	return apiPlayer{
+		Name:         p.Name,
+		Number:       p.Number,
+		PlayCount:    p.PlayCount,
+		PlayDuration: p.PlayDuration.Round(time.Second).String(),
+		PlaySeconds:  p.PlayDuration.Seconds(),
+		Playing:      p.Playing,
+		PlayStarted:  optionalTime(p.PlayStarted),
+		Points:       p.Points,
+		Assists:      p.Assists,
+
+		Position:        p.Position,
+		PositionSeconds: positionSeconds(p.PositionDurations),
+		Availability:    Availability(p.Availability.String()),
+	}
+}
+
+// positionSeconds converts the time played in each position to seconds.
+func positionSeconds(durations map[string]time.Duration) map[string]float64 {
+	seconds := make(map[string]float64, len(durations))
+	for pos, d := range durations {
+		seconds[pos] = d.Seconds()
+	}
+
+	return seconds
+}
+
+func newAPIPlayers(players []Player) []apiPlayer {
+	ps := make([]apiPlayer, 0, len(players))
+	for _, p := range players {
+		ps = append(ps, newAPIPlayer(p))
+	}
+
+	return ps
+}
+
+func (ws *WebServer) respondJSON(status int, v any, w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		ws.logger.Error("json.Encode()", "error", err.Error())
+	}
+}
+
+func (ws *WebServer) respondJSONError(status int, err error, w http.ResponseWriter, r *http.Request) {
+	ws.logger.Error("respondJSONError()", "error", err.Error())
+
+	ws.respondJSON(status, apiError{Error: err.Error()}, w, r)
+}
+
+// apiTeam returns the Subber for the team in the request path, responding with
+// an error if there isn't one.
+func (ws *WebServer) apiTeam(w http.ResponseWriter, r *http.Request) (*Subber, bool) {
+	name := r.PathValue("team")
+
+	if s, ok := ws.findTeam(name); ok {
+		return s, true
+	}
+
+	ws.respondJSONError(http.StatusNotFound, fmt.Errorf("team not found: %q", name), w, r)
+
+	return nil, false
+}
+
+func (ws *WebServer) apiListTeams(w http.ResponseWriter, r *http.Request) {
+	teams := make([]apiTeam, 0, len(ws.subbers))
+	for _, s := range ws.subbers {
+		teams = append(teams, apiTeam{Name: s.Name()})
+	}
+
+	ws.respondJSON(http.StatusOK, teams, w, r)
+}
+
+func (ws *WebServer) apiGetGame(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIGame(s), w, r)
+}
+
+// apiGameAction returns a handler performing the game action and responding
+// with the resulting game.
+func (ws *WebServer) apiGameAction(action func(*Subber) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := ws.apiTeam(w, r)
+		if !ok {
+			return
+		}
+
+		if err := action(s); err != nil {
+			ws.respondJSONError(errorStatus(err), err, w, r)
+
+			return
+		}
+
+		ws.respondJSON(http.StatusOK, newAPIGame(s), w, r)
+	}
+}
+
+func (ws *WebServer) apiListEvents(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, s.Events(), w, r)
+}
+
+func (ws *WebServer) apiGetSeason(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	stats := s.Season()
+
+	season := make([]apiPlayerSeason, 0, len(stats))
+	for _, ps := range stats {
+		season = append(season, apiPlayerSeason{
+			Name:         ps.Name,
+			Number:       ps.Number,
+			Games:        ps.Games,
+			PlayCount:    ps.PlayCount,
+			PlayDuration: ps.PlayDuration.Round(time.Second).String(),
+			PlaySeconds:  ps.PlayDuration.Seconds(),
+			Share:        ps.Share,
+			Points:       ps.Points,
+			Assists:      ps.Assists,
+
+			PositionSeconds: positionSeconds(ps.PositionDurations),
+		})
+	}
+
+	ws.respondJSON(http.StatusOK, season, w, r)
+}
+
+func (ws *WebServer) apiListPlayers(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiGetPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	name := r.PathValue("name")
+
+	for _, p := range s.ListPlayers() {
+		if p.Name == name {
+			ws.respondJSON(http.StatusOK, newAPIPlayer(p), w, r)
+
+			return
+		}
+	}
+
+	ws.respondJSONError(http.StatusNotFound, fmt.Errorf("%w: %s", ErrPlayerNotFound, name), w, r)
+}
+
+// apiPlayerAction returns a handler performing the player action and
+// responding with all players.
+func (ws *WebServer) apiPlayerAction(action func(*Subber, string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := ws.apiTeam(w, r)
+		if !ok {
+			return
+		}
+
+		if err := action(s, r.PathValue("name")); err != nil {
+			ws.respondJSONError(errorStatus(err), err, w, r)
+
+			return
+		}
+
+		ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+	}
+}
+
+// apiSubOn subs on a player, filling the position in the optional body.
+func (ws *WebServer) apiSubOn(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiSubOnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.PlayerSubOnPosition(r.PathValue("name"), req.Position); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiSetAvailability(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiAvailabilityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.PlayerAvailability(r.PathValue("name"), req.Availability); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiAddPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiRosterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.AddPlayer(req.Name, req.Number); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiUpdatePlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiRosterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.UpdatePlayer(r.PathValue("name"), req.Name, req.Number); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiSetPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiSetPlayerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	duration, err := time.ParseDuration(req.PlayDuration)
+	if err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing duration: %v", err), w, r)
+
+		return
+	}
+
+	if req.PlayCount < 0 || duration < 0 {
+		ws.respondJSONError(http.StatusBadRequest, errors.New("play count and duration must not be negative"), w, r)
+
+		return
+	}
+
+	if err := s.PlayerSet(r.PathValue("name"), req.PlayCount, duration); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
+
+func (ws *WebServer) apiScore(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := decodeScoreRequest(r)
+	if err != nil {
+		ws.respondJSONError(http.StatusBadRequest, err, w, r)
+
+		return
+	}
+
+	if err := s.Score(req.Player, req.Assist, req.Points); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIGame(s), w, r)
+}
+
+func (ws *WebServer) apiOpponentScore(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	req, err := decodeScoreRequest(r)
+	if err != nil {
+		ws.respondJSONError(http.StatusBadRequest, err, w, r)
+
+		return
+	}
+
+	if req.Player != "" || req.Assist != "" {
+		err := fmt.Errorf("%w: opponent scores have no scorer", ErrInvalidScore)
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	if err := s.OpponentScore(req.Points); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIGame(s), w, r)
+}
+
+// decodeScoreRequest decodes the body of a score, defaulting to 1 point.
+func decodeScoreRequest(r *http.Request) (apiScoreRequest, error) {
+	var req apiScoreRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return req, fmt.Errorf("parsing body: %v", err)
+	}
+
+	if req.Points == 0 {
+		req.Points = 1
+	}
+
+	return req, nil
+}
+
+func (ws *WebServer) apiSwap(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.apiTeam(w, r)
+	if !ok {
+		return
+	}
+
+	var req apiSwapRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.Swap(req.Off, req.On); err != nil {
+		ws.respondJSONError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, newAPIPlayers(s.ListPlayers()), w, r)
+}
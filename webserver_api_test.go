@@ -0,0 +1,163 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWebServer_api(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{
+		Name:      "tigers",
+		Players:   []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}, {Name: "mary", Number: 3}},
+		Positions: []string{"GK", "C"},
+	})
+
+	ws := &WebServer{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), subbers: []*Subber{s}}
+	mux := http.NewServeMux()
+	ws.setAPIRoutes(mux)
+
+	// each request builds on the game left by the requests before it.
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		// err is the message of an error response.
+		err string
+	}{
+		{
+			name:   "start game",
+			path:   "/api/v1/teams/tigers/game/start",
+			status: http.StatusOK,
+		},
+		{
+			name:   "start started game",
+			path:   "/api/v1/teams/tigers/game/start",
+			status: http.StatusConflict,
+			err:    "game already started",
+		},
+		{
+			name:   "sub on without position",
+			path:   "/api/v1/teams/tigers/players/jane/sub-on",
+			status: http.StatusOK,
+		},
+		{
+			name:   "sub on to unknown position",
+			path:   "/api/v1/teams/tigers/players/mary/sub-on",
+			body:   `{"position":"WD"}`,
+			status: http.StatusBadRequest,
+			err:    "invalid position: WD",
+		},
+		{
+			name:   "sub on with position",
+			path:   "/api/v1/teams/tigers/players/john/sub-on",
+			body:   `{"position":"GK"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "sub on to filled position",
+			path:   "/api/v1/teams/tigers/players/jane/sub-on",
+			body:   `{"position":"GK"}`,
+			status: http.StatusConflict,
+			err:    "position filled, sub a player off first: john plays GK",
+		},
+		{
+			name:   "sub on with full field",
+			path:   "/api/v1/teams/tigers/players/mary/sub-on",
+			status: http.StatusConflict,
+			err:    "field full, sub a player off first",
+		},
+		{
+			name:   "swap with full field",
+			path:   "/api/v1/teams/tigers/subs/swap",
+			body:   `{"off":[],"on":["mary"]}`,
+			status: http.StatusConflict,
+			err:    "field full, sub a player off first",
+		},
+		{
+			name:   "swap",
+			path:   "/api/v1/teams/tigers/subs/swap",
+			body:   `{"off":["john"],"on":["mary"]}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "sub on unknown player",
+			path:   "/api/v1/teams/tigers/players/bob/sub-on",
+			status: http.StatusNotFound,
+			err:    "player not found: bob",
+		},
+		{
+			name:   "swap unknown player",
+			path:   "/api/v1/teams/tigers/subs/swap",
+			body:   `{"off":["mary"],"on":["bob"]}`,
+			status: http.StatusNotFound,
+			err:    "player not found: bob",
+		},
+		{
+			name:   "unknown team",
+			path:   "/api/v1/teams/lions/game/start",
+			status: http.StatusNotFound,
+			err:    `team not found: "lions"`,
+		},
+		{
+			name:   "sub on with bad json",
+			path:   "/api/v1/teams/tigers/players/john/sub-on",
+			body:   `{"position":`,
+			status: http.StatusBadRequest,
+			err:    "parsing body: unexpected EOF",
+		},
+		{
+			name:   "swap with bad json",
+			path:   "/api/v1/teams/tigers/subs/swap",
+			body:   `off=john`,
+			status: http.StatusBadRequest,
+			err:    "parsing body: invalid character 'o' looking for beginning of value",
+		},
+	}
+
+	for _, tc := range tests {
+		r := httptest.NewRequest(http.MethodPost, tc.path, strings.NewReader(tc.body))
+		r.Header.Set("Content-Type", "application/json")
+
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%s: status = %d, want %d, body %s", tc.name, w.Code, tc.status, w.Body)
+
+			continue
+		}
+
+		if tc.err == "" {
+			continue
+		}
+
+		var got apiError
+		if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+			t.Fatalf("%s: failed to decode error: %v", tc.name, err)
+		}
+
+		if diff := cmp.Diff(apiError{Error: tc.err}, got); diff != "" {
+			t.Errorf("%s: error mismatch (-want +got):\n%s", tc.name, diff)
+		}
+	}
+
+	want := []Player{
+		{Name: "jane", Number: 1, PlayCount: 1, Playing: true},
+		{Name: "john", Number: 2, PlayCount: 1},
+		{Name: "mary", Number: 3, PlayCount: 1, Playing: true, Position: "GK"},
+	}
+
+	ignore := cmpopts.IgnoreFields(Player{}, "PlayDuration", "PlayStarted", "RestStarted", "PositionDurations")
+	if diff := cmp.Diff(want, s.ListPlayers(), ignore); diff != "" {
+		t.Errorf("ListPlayers() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	sessionCookieName = "gosubs_session"
+	// loginFailureDelay slows down guessing of a short PIN.
+	loginFailureDelay = time.Second
+)
+
+// viewer is who made a request, available to templates from the context.
+type viewer struct {
+	// Coach is the name of the logged in coach, empty when not logged in.
+	Coach string
+	// CanEdit is true for coaches, or everyone when authentication is disabled.
+	CanEdit bool
+}
+
+func withViewer(ctx context.Context, v viewer) context.Context {
+	return context.WithValue(ctx, viewerContextKey, v)
+}
+
+func viewerFrom(ctx context.Context) viewer {
+	v, _ := ctx.Value(viewerContextKey).(viewer)
+
+	return v
+}
+
+// canEdit returns true when the viewer may change the game and players.
+func canEdit(ctx context.Context) bool {
+	return viewerFrom(ctx).CanEdit
+}
+
+// loggedIn returns true when the viewer is a logged in coach.
+func loggedIn(ctx context.Context) bool {
+	return viewerFrom(ctx).Coach != ""
+}
+
+// sessionToken returns the session token from the bearer token used by API
+// clients, or the session cookie used by browsers.
+func sessionToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return token
+	}
+
+	if c, err := r.Cookie(sessionCookieName); err == nil {
+		return c.Value
+	}
+
+	return ""
+}
+
+// isSafeMethod returns true for methods that do not change anything.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// authMiddleware adds the viewer to the request context and rejects changes by
+// anyone other than a logged in coach, leaving pages read only.
+func (ws *WebServer) authMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !ws.auth.enabled() {
+			next(w, r.WithContext(withViewer(r.Context(), viewer{CanEdit: true})))
+
+			return
+		}
+
+		if s, ok := ws.auth.lookup(sessionToken(r), time.Now()); ok {
+			next(w, r.WithContext(withViewer(r.Context(), viewer{Coach: s.Coach, CanEdit: true})))
+
+			return
+		}
+
+		switch {
+		case isSafeMethod(r.Method),
+			r.URL.Path == "/login",
+			r.URL.Path == "/api/v1/login":
+			next(w, r.WithContext(withViewer(r.Context(), viewer{})))
+		case strings.HasPrefix(r.URL.Path, "/api/"):
+			w.Header().Set("WWW-Authenticate", `Bearer realm="gosubs"`)
+			ws.respondJSONError(http.StatusUnauthorized, errors.New("login required"), w, r)
+		case r.Header.Get("HX-Request") == "true":
+			// htmx follows the redirect instead of swapping in the error.
+			w.Header().Set("HX-Redirect", loginPath(r))
+			ws.respondError(http.StatusUnauthorized, errors.New("login required"), w, r)
+		default:
+			http.Redirect(w, r, loginPath(r), http.StatusSeeOther)
+		}
+	}
+}
+
+// loginPath returns the path of the login page, returning to the page the
+// request was made from after logging in.
+func loginPath(r *http.Request) string {
+	from := r.Header.Get("HX-Current-URL")
+	if from == "" {
+		from = r.Referer()
+	}
+
+	u, err := url.Parse(from)
+	if err != nil || u.Host != r.Host {
+		return "/login"
+	}
+
+	return "/login?next=" + url.QueryEscape(u.RequestURI())
+}
+
+// setSessionCookie stores the session token in the browser, or removes it when
+// the token is empty.
+func (ws *WebServer) setSessionCookie(w http.ResponseWriter, token string, expires time.Time) {
+	c := &http.Cookie{
+		Name:     sessionCookieName,
+		Value:    token,
+		Path:     "/",
+		Expires:  expires,
+		Secure:   ws.tls,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+
+	if token == "" {
+		c.MaxAge = -1
+	}
+
+	http.SetCookie(w, c)
+}
+
+// localRedirect returns the path to redirect to after logging in, ignoring
+// anything that would leave this site.
+func localRedirect(next string) string {
+	u, err := url.Parse(next)
+	if err != nil || u.IsAbs() || u.Host != "" ||
+		!strings.HasPrefix(u.Path, "/") || strings.HasPrefix(u.Path, "//") ||
+		strings.Contains(next, `\`) {
+		return "/"
+	}
+
+	return u.RequestURI()
+}
+
+// loginView renders the login form.
+func (ws *WebServer) loginView(status int, next, message string, w http.ResponseWriter, r *http.Request) {
+	askName := len(ws.auth.config.Coaches) > 0
+
+	secret := "PIN"
+	switch {
+	case askName && ws.auth.config.PIN != "":
+		secret = "Password or PIN"
+	case askName:
+		secret = "Password"
+	}
+
+	tc := layout("Go Subs - Log in", "Coach log in", login(askName, secret, next, message))
+	ws.renderTemplate(status, tc, w, r)
+}
+
+// getLogin shows the login form.
+func (ws *WebServer) getLogin(w http.ResponseWriter, r *http.Request) {
+	next := localRedirect(r.URL.Query().Get("next"))
+
+	if !ws.auth.enabled() || loggedIn(r.Context()) {
+		http.Redirect(w, r, next, http.StatusSeeOther)
+
+		return
+	}
+
+	ws.loginView(http.StatusOK, next, "", w, r)
+}
+
+// postLogin starts a session for a coach with valid credentials.
+func (ws *WebServer) postLogin(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		ws.loginView(http.StatusBadRequest, "/", "Unable to read the form, please try again.", w, r)
+
+		return
+	}
+
+	next := localRedirect(r.PostForm.Get("next"))
+
+	token, s, err := ws.auth.login(r.PostForm.Get("name"), r.PostForm.Get("password"), time.Now())
+	if err != nil {
+		ws.logger.Warn("postLogin()", "error", err.Error(), "remote", r.RemoteAddr)
+		time.Sleep(loginFailureDelay)
+		ws.loginView(http.StatusUnauthorized, next, "Incorrect name, password or PIN.", w, r)
+
+		return
+	}
+
+	ws.logger.Info("coach logged in", "coach", s.Coach, "remote", r.RemoteAddr)
+	ws.setSessionCookie(w, token, s.Expires)
+	http.Redirect(w, r, next, http.StatusSeeOther)
+}
+
+// postLogout ends the coach's session.
+func (ws *WebServer) postLogout(w http.ResponseWriter, r *http.Request) {
+	ws.auth.logout(sessionToken(r))
+	ws.setSessionCookie(w, "", time.Time{})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+type apiLoginRequest struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+type apiLoginResponse struct {
+	// Token to send as `Authorization: Bearer <token>`.
+	Token   string    `json:"token"`
+	Expires time.Time `json:"expires"`
+}
+
+// apiLogin returns a bearer token for API clients with valid credentials.
+func (ws *WebServer) apiLogin(w http.ResponseWriter, r *http.Request) {
+	var req apiLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ws.respondJSONError(http.StatusBadRequest, fmt.Errorf("parsing body: %v", err), w, r)
+
+		return
+	}
+
+	token, s, err := ws.auth.login(req.Name, req.Password, time.Now())
+	if err != nil {
+		time.Sleep(loginFailureDelay)
+		ws.respondJSONError(http.StatusUnauthorized, err, w, r)
+
+		return
+	}
+
+	ws.respondJSON(http.StatusOK, apiLoginResponse{Token: token, Expires: s.Expires}, w, r)
+}
+
+// apiLogout ends the session of the bearer token.
+func (ws *WebServer) apiLogout(w http.ResponseWriter, r *http.Request) {
+	ws.auth.logout(sessionToken(r))
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebServer_authMiddleware(t *testing.T) {
+	pin := AuthConfig{PIN: "2468"}
+
+	const token = "session"
+
+	tests := []struct {
+		name     string
+		auth     AuthConfig
+		method   string
+		path     string
+		token    string
+		status   int
+		location string
+	}{
+		{
+			name:   "team page without login configured",
+			method: http.MethodGet,
+			path:   "/teams/tigers/",
+			status: http.StatusOK,
+		},
+		{
+			name:   "team list",
+			auth:   pin,
+			method: http.MethodGet,
+			path:   "/teams",
+			status: http.StatusOK,
+		},
+		{
+			name:   "share link",
+			auth:   pin,
+			method: http.MethodGet,
+			path:   "/share/token/",
+			status: http.StatusOK,
+		},
+		{
+			name:   "login",
+			auth:   pin,
+			method: http.MethodPost,
+			path:   "/login",
+			status: http.StatusOK,
+		},
+		{
+			name:   "team page",
+			auth:   pin,
+			method: http.MethodGet,
+			path:   "/teams/tigers/season",
+			status: http.StatusOK,
+		},
+		{
+			name:     "team change",
+			auth:     pin,
+			method:   http.MethodPost,
+			path:     "/teams/tigers/game/start",
+			status:   http.StatusSeeOther,
+			location: "/login",
+		},
+		{
+			name:   "team page as coach",
+			auth:   pin,
+			method: http.MethodGet,
+			path:   "/teams/tigers/season",
+			token:  token,
+			status: http.StatusOK,
+		},
+		{
+			name:   "api players",
+			auth:   pin,
+			method: http.MethodGet,
+			path:   "/api/v1/teams/tigers/players",
+			status: http.StatusOK,
+		},
+		{
+			name:   "api change",
+			auth:   pin,
+			method: http.MethodPost,
+			path:   "/api/v1/teams/tigers/game/start",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "api change as coach",
+			auth:   pin,
+			method: http.MethodPost,
+			path:   "/api/v1/teams/tigers/game/start",
+			token:  token,
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ws := &WebServer{
+				logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+				auth:   newAuthenticator(tc.auth),
+			}
+
+			if tc.token != "" {
+				ws.auth.sessions[tc.token] = session{Coach: pinCoachName, Expires: time.Now().Add(time.Hour)}
+			}
+
+			handler := ws.authMiddleware(func(w http.ResponseWriter, r *http.Request) {})
+
+			r := httptest.NewRequest(tc.method, tc.path, nil)
+			if tc.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tc.token)
+			}
+
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("status = %d, want %d", w.Code, tc.status)
+			}
+
+			if got := w.Header().Get("Location"); got != tc.location {
+				t.Errorf("Location = %q, want %q", got, tc.location)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"slices"
+	"time"
+)
+
+const (
+	csrfCookieName = "gosubs_csrf"
+	csrfCookieAge  = 365 * 24 * time.Hour
+	// csrfHeaderName is sent by htmx, see csrfHeaders.
+	csrfHeaderName = "X-CSRF-Token"
+	// csrfFieldName is sent by plain forms, see csrfField.
+	csrfFieldName = "csrf_token"
+)
+
+var ErrCSRFTokenInvalid = errors.New("missing or incorrect CSRF token")
+
+// csrfToken returns the CSRF token to include in forms and htmx requests.
+func csrfToken(ctx context.Context) string {
+	token, _ := ctx.Value(csrfContextKey).(string)
+
+	return token
+}
+
+// csrfHeaders returns the `hx-headers` sending the CSRF token with every htmx
+// request.
+func csrfHeaders(ctx context.Context) string {
+	b, err := json.Marshal(map[string]string{csrfHeaderName: csrfToken(ctx)})
+	if err != nil {
+		return "{}"
+	}
+
+	return string(b)
+}
+
+// crossSiteSafe returns true for requests a browser can't send from another
+// site without the CORS approval of corsMiddleware: those with a bearer token
+// or a JSON body, which are always used by the JSON API.
+func crossSiteSafe(r *http.Request) bool {
+	if r.Header.Get("Authorization") != "" {
+		return true
+	}
+
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	return err == nil && mediaType == "application/json"
+}
+
+// csrfMiddleware rejects changes unless they include the token from the CSRF
+// cookie, which pages on other sites can't read, so a page a coach visits can't
+// make changes on their behalf. The token is added to the request context for
+// templates.
+func (ws *WebServer) csrfMiddleware(next func(w http.ResponseWriter, r *http.Request)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var token string
+		if c, err := r.Cookie(csrfCookieName); err == nil && c.Value != "" {
+			token = c.Value
+		}
+
+		if !isSafeMethod(r.Method) && !crossSiteSafe(r) {
+			got := r.Header.Get(csrfHeaderName)
+			if got == "" {
+				got = r.PostFormValue(csrfFieldName)
+			}
+
+			if token == "" || !equal(token, got) {
+				if r.Header.Get("HX-Request") == "true" {
+					// reload the page for a new token, such as when cookies were cleared.
+					w.Header().Set("HX-Refresh", "true")
+				}
+
+				ws.respondError(http.StatusForbidden, ErrCSRFTokenInvalid, w, r)
+
+				return
+			}
+		}
+
+		if token == "" {
+			var err error
+
+			token, err = randomToken()
+			if err != nil {
+				ws.respondError(http.StatusInternalServerError, err, w, r)
+
+				return
+			}
+
+			http.SetCookie(w, &http.Cookie{
+				Name:     csrfCookieName,
+				Value:    token,
+				Path:     "/",
+				MaxAge:   int(csrfCookieAge.Seconds()),
+				Secure:   ws.tls,
+				HttpOnly: true,
+				SameSite: http.SameSiteLaxMode,
+			})
+		}
+
+		next(w, r.WithContext(context.WithValue(r.Context(), csrfContextKey, token)))
+	}
+}
+
+// allowedOrigin returns true when the origin may make requests with the
+// credentials of a coach, such as a scoreboard display hosted elsewhere.
+func (ws *WebServer) allowedOrigin(origin string) bool {
+	return slices.Contains(ws.allowedOrigins, origin)
+}
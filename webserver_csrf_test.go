@@ -0,0 +1,123 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestWebServer_csrfMiddleware(t *testing.T) {
+	ws := &WebServer{logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	handler := ws.csrfMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if csrfToken(r.Context()) == "" {
+			t.Error("csrfToken() is empty in handler")
+		}
+	})
+
+	const token = "token"
+
+	cookie := &http.Cookie{Name: csrfCookieName, Value: token}
+	form := url.Values{csrfFieldName: {token}}.Encode()
+
+	tests := []struct {
+		name   string
+		req    func() *http.Request
+		status int
+	}{
+		{
+			name: "get without token",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/", nil)
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "post without token",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/teams/tigers/game/reset", nil)
+				r.AddCookie(cookie)
+
+				return r
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "post without cookie",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/teams/tigers/game/reset", nil)
+				r.Header.Set(csrfHeaderName, token)
+
+				return r
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "post with incorrect header",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/teams/tigers/game/reset", nil)
+				r.AddCookie(cookie)
+				r.Header.Set(csrfHeaderName, "other")
+
+				return r
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "post with header",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/teams/tigers/game/reset", nil)
+				r.AddCookie(cookie)
+				r.Header.Set(csrfHeaderName, token)
+
+				return r
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "post with form field",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form))
+				r.AddCookie(cookie)
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+				return r
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "post json",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/api/v1/teams/tigers/game/reset", nil)
+				r.Header.Set("Content-Type", "application/json; charset=utf-8")
+
+				return r
+			},
+			status: http.StatusOK,
+		},
+		{
+			name: "post with bearer token",
+			req: func() *http.Request {
+				r := httptest.NewRequest(http.MethodPost, "/api/v1/teams/tigers/game/reset", nil)
+				r.Header.Set("Authorization", "Bearer session")
+
+				return r
+			},
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler(w, tc.req())
+
+			if w.Code != tc.status {
+				t.Errorf("status = %d, want %d", w.Code, tc.status)
+			}
+		})
+	}
+}
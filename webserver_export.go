@@ -0,0 +1,264 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// setExportRoutes registers downloads of game and season statistics for
+// spreadsheets, and a match report to print.
+func (ws *WebServer) setExportRoutes(mux *http.ServeMux) {
+	// the current game, or an archived game with `?game=N` numbered from 1.
+	mux.HandleFunc("GET /teams/{team}/export/game.csv", ws.exportGameCSV)
+	mux.HandleFunc("GET /teams/{team}/export/game.json", ws.exportGameJSON)
+	mux.HandleFunc("GET /teams/{team}/export/season.csv", ws.exportSeasonCSV)
+	mux.HandleFunc("GET /teams/{team}/report", ws.getReport)
+}
+
+type exportGame struct {
+	Team          string         `json:"team"`
+	StartTime     time.Time      `json:"startTime"`
+	EndTime       *time.Time     `json:"endTime"`
+	Periods       []apiPeriod    `json:"periods"`
+	Score         int            `json:"score"`
+	OpponentScore int            `json:"opponentScore"`
+	Players       []exportPlayer `json:"players"`
+}
+
+type exportPlayer struct {
+	Name        string  `json:"name"`
+	Number      int     `json:"number"`
+	PlayCount   int     `json:"playCount"`
+	PlaySeconds float64 `json:"playSeconds"`
+	// PeriodSeconds is the time played in each period.
+	PeriodSeconds []float64     `json:"periodSeconds"`
+	Points        int           `json:"points"`
+	Assists       int           `json:"assists"`
+	Stints        []exportStint `json:"stints"`
+	Availability  Availability  `json:"availability"`
+}
+
+type exportStint struct {
+	Position string     `json:"position,omitempty"`
+	Start    time.Time  `json:"start"`
+	End      *time.Time `json:"end"`
+}
+
+func newExportGame(report GameReport) exportGame {
+	gr := report.Game
+
+	periods := make([]apiPeriod, 0, len(gr.Periods))
+	for _, p := range gr.Periods {
+		periods = append(periods, apiPeriod{StartTime: p.StartTime, EndTime: optionalTime(p.EndTime)})
+	}
+
+	players := make([]exportPlayer, 0, len(report.Players))
+	for _, p := range report.Players {
+		seconds := make([]float64, 0, len(p.PeriodDurations))
+		for _, d := range p.PeriodDurations {
+			seconds = append(seconds, d.Seconds())
+		}
+
+		stints := make([]exportStint, 0, len(p.Stints))
+		for _, st := range p.Stints {
+			stints = append(stints, exportStint{Position: st.Position, Start: st.Start, End: optionalTime(st.End)})
+		}
+
+		players = append(players, exportPlayer{
+			Name:          p.Name,
+			Number:        p.Number,
+			PlayCount:     p.PlayCount,
+			PlaySeconds:   p.PlayDuration.Seconds(),
+			PeriodSeconds: seconds,
+			Points:        p.Points,
+			Assists:       p.Assists,
+			Stints:        stints,
+			Availability:  Availability(p.Availability.String()),
+		})
+	}
+
+	return exportGame{
+		Team:          report.Team,
+		StartTime:     gr.StartTime,
+		EndTime:       optionalTime(gr.EndTime),
+		Periods:       periods,
+		Score:         gr.Score,
+		OpponentScore: gr.OpponentScore,
+		Players:       players,
+	}
+}
+
+// gameReport returns the report of the game requested with `?game=N`, the
+// current game by default, responding with an error if there isn't one.
+func (ws *WebServer) gameReport(w http.ResponseWriter, r *http.Request) (GameReport, bool) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return GameReport{}, false
+	}
+
+	var game int
+
+	if v := r.URL.Query().Get("game"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing game: %v", err), w, r)
+
+			return GameReport{}, false
+		}
+
+		game = n
+	}
+
+	report, err := s.GameReport(game)
+	if err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return GameReport{}, false
+	}
+
+	return report, true
+}
+
+// attachment names the file a response is downloaded as.
+func attachment(w http.ResponseWriter, filename string) {
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+}
+
+// minutes formats a duration as decimal minutes for spreadsheets.
+func minutes(d time.Duration) string {
+	return strconv.FormatFloat(d.Minutes(), 'f', 2, 64)
+}
+
+// stintsLabel describes stints such as `09:00:00-09:12:00 GK`.
+func stintsLabel(stints []Stint) string {
+	labels := make([]string, 0, len(stints))
+
+	for _, st := range stints {
+		label := st.Start.Format(time.TimeOnly) + "-"
+		if !st.End.IsZero() {
+			label += st.End.Format(time.TimeOnly)
+		}
+
+		if st.Position != "" {
+			label += " " + st.Position
+		}
+
+		labels = append(labels, label)
+	}
+
+	return strings.Join(labels, "; ")
+}
+
+// writeCSV writes the records as a CSV download, logging failures as the
+// response has already started.
+func (ws *WebServer) writeCSV(filename string, records [][]string, w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	attachment(w, filename)
+
+	if err := csv.NewWriter(w).WriteAll(records); err != nil {
+		ws.logger.Error("csv.WriteAll()", "error", err.Error())
+	}
+}
+
+// exportGameCSV downloads a row per player with their minutes in each period
+// and the stints they played.
+func (ws *WebServer) exportGameCSV(w http.ResponseWriter, r *http.Request) {
+	report, ok := ws.gameReport(w, r)
+	if !ok {
+		return
+	}
+
+	header := []string{"number", "name", "availability", "count", "minutes"}
+	for i := range report.Game.Periods {
+		header = append(header, fmt.Sprintf("period_%d_minutes", i+1))
+	}
+
+	header = append(header, "points", "assists", "stints")
+
+	records := [][]string{header}
+
+	for _, p := range report.Players {
+		record := []string{
+			strconv.Itoa(p.Number),
+			p.Name,
+			p.Availability.String(),
+			strconv.Itoa(p.PlayCount),
+			minutes(p.PlayDuration),
+		}
+
+		for _, d := range p.PeriodDurations {
+			record = append(record, minutes(d))
+		}
+
+		record = append(record,
+			strconv.Itoa(p.Points),
+			strconv.Itoa(p.Assists),
+			stintsLabel(p.Stints),
+		)
+
+		records = append(records, record)
+	}
+
+	ws.writeCSV(reportFilename(report, "csv"), records, w)
+}
+
+// exportGameJSON downloads the game with every player's stints.
+func (ws *WebServer) exportGameJSON(w http.ResponseWriter, r *http.Request) {
+	report, ok := ws.gameReport(w, r)
+	if !ok {
+		return
+	}
+
+	attachment(w, reportFilename(report, "json"))
+	ws.respondJSON(http.StatusOK, newExportGame(report), w, r)
+}
+
+// exportSeasonCSV downloads a row per player totalled across archived games.
+func (ws *WebServer) exportSeasonCSV(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	records := [][]string{{"number", "name", "games", "count", "minutes", "share", "points", "assists"}}
+
+	for _, ps := range s.Season() {
+		records = append(records, []string{
+			strconv.Itoa(ps.Number),
+			ps.Name,
+			strconv.Itoa(ps.Games),
+			strconv.Itoa(ps.PlayCount),
+			minutes(ps.PlayDuration),
+			strconv.FormatFloat(ps.Share, 'f', 3, 64),
+			strconv.Itoa(ps.Points),
+			strconv.Itoa(ps.Assists),
+		})
+	}
+
+	ws.writeCSV(s.Name()+"-season.csv", records, w)
+}
+
+// reportFilename names a game's download, such as `tigers-2025-03-01.csv`.
+func reportFilename(report GameReport, ext string) string {
+	return fmt.Sprintf("%s-%s.%s", report.Team, report.Game.StartTime.Format(time.DateOnly), ext)
+}
+
+// getReport shows a match report formatted for printing or saving as a PDF.
+func (ws *WebServer) getReport(w http.ResponseWriter, r *http.Request) {
+	report, ok := ws.gameReport(w, r)
+	if !ok {
+		return
+	}
+
+	query := ""
+	if game := r.URL.Query().Get("game"); game != "" {
+		query = "?game=" + game
+	}
+
+	tc := layout("Go Subs - Match Report", "Match report", matchReport(teamPath(report.Team), query, report))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// setRosterRoutes registers the roster page, for coaches to add, rename,
+// renumber and remove players, or import them from CSV, without editing the
+// config file.
+func (ws *WebServer) setRosterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /teams/{team}/roster", ws.getRoster)
+	mux.HandleFunc("POST /teams/{team}/roster", ws.addPlayer)
+	mux.HandleFunc("POST /teams/{team}/roster/{name}", ws.updatePlayer)
+	mux.HandleFunc("POST /teams/{team}/roster/{name}/remove", ws.removePlayer)
+	mux.HandleFunc("POST /teams/{team}/import", ws.importRoster)
+}
+
+// rosterView renders the team's roster with a message, such as why a change
+// was rejected, and any problems importing players.
+func (ws *WebServer) rosterView(status int, s *Subber, message string, problems []error, w http.ResponseWriter, r *http.Request) {
+	tc := layout("Go Subs - Roster", "Manage the roster", roster(teamPath(s.Name()), s.ListPlayers(), message, problems))
+	ws.renderTemplate(status, tc, w, r)
+}
+
+// rosterChange performs the change to the roster from the submitted form,
+// redirecting back to the roster or showing why the change was rejected.
+func (ws *WebServer) rosterChange(change func(s *Subber, name string, number int) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s, ok := ws.team(w, r)
+		if !ok {
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			ws.rosterView(http.StatusBadRequest, s, "Unable to read the form, please try again.", nil, w, r)
+
+			return
+		}
+
+		var number int
+
+		if v := strings.TrimSpace(r.PostForm.Get("number")); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				ws.rosterView(http.StatusBadRequest, s, fmt.Sprintf("Invalid number %q.", v), nil, w, r)
+
+				return
+			}
+
+			number = n
+		}
+
+		if err := change(s, strings.TrimSpace(r.PostForm.Get("name")), number); err != nil {
+			ws.logger.Warn("rosterChange()", "error", err.Error())
+			ws.rosterView(errorStatus(err), s, err.Error(), nil, w, r)
+
+			return
+		}
+
+		http.Redirect(w, r, teamPath(s.Name())+"/roster", http.StatusSeeOther)
+	}
+}
+
+// getRoster shows the team's players for coaches to change.
+func (ws *WebServer) getRoster(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	ws.rosterView(http.StatusOK, s, "", nil, w, r)
+}
+
+// addPlayer adds the player named in the form.
+func (ws *WebServer) addPlayer(w http.ResponseWriter, r *http.Request) {
+	ws.rosterChange(func(s *Subber, name string, number int) error {
+		return s.AddPlayer(name, number)
+	})(w, r)
+}
+
+// updatePlayer renames and renumbers the player in the request path.
+func (ws *WebServer) updatePlayer(w http.ResponseWriter, r *http.Request) {
+	ws.rosterChange(func(s *Subber, name string, number int) error {
+		return s.UpdatePlayer(r.PathValue("name"), name, number)
+	})(w, r)
+}
+
+// removePlayer removes the player in the request path.
+func (ws *WebServer) removePlayer(w http.ResponseWriter, r *http.Request) {
+	ws.rosterChange(func(s *Subber, _ string, _ int) error {
+		return s.RemovePlayer(r.PathValue("name"))
+	})(w, r)
+}
+
+// importRoster merges the players of an uploaded CSV file into the roster,
+// showing what changed and the rows skipped.
+func (ws *WebServer) importRoster(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	file, _, err := r.FormFile("roster")
+	if err != nil {
+		ws.rosterView(http.StatusBadRequest, s, "Choose a CSV file to import.", nil, w, r)
+
+		return
+	}
+	defer file.Close()
+
+	columns := ImportColumns{
+		Name:         splitColumns(r.PostFormValue("name")),
+		Number:       strings.TrimSpace(r.PostFormValue("number")),
+		Position:     strings.TrimSpace(r.PostFormValue("position")),
+		Availability: strings.TrimSpace(r.PostFormValue("availability")),
+	}
+
+	players, problems, err := readRoster(file, columns)
+	if err != nil {
+		ws.logger.Warn("importRoster()", "error", err.Error())
+		ws.rosterView(http.StatusBadRequest, s, err.Error(), nil, w, r)
+
+		return
+	}
+
+	result := s.ImportPlayers(players)
+	result.Problems = append(problems, result.Problems...)
+
+	ws.rosterView(http.StatusOK, s, "Imported players: "+result.Summary()+".", result.Problems, w, r)
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"time"
 )
@@ -14,28 +16,71 @@ func (ws *WebServer) setRoutes() {
 	mwMux := http.NewServeMux()
 	ws.mux.Handle("/", ws.middlewareChain(mwMux))
 
-	// home & team actions
+	// home & team picker
 	mwMux.HandleFunc("GET /{$}", ws.home)
+	mwMux.HandleFunc("GET /teams", ws.listTeams)
 
-	// refresh team automatically
-	mwMux.HandleFunc("GET /game", ws.getGame)
+	// coach login, changes are rejected without one when authentication is enabled.
+	mwMux.HandleFunc("GET /login", ws.getLogin)
+	mwMux.HandleFunc("POST /login", ws.postLogin)
+	mwMux.HandleFunc("POST /logout", ws.postLogout)
+
+	// team actions
+	mwMux.HandleFunc("GET /teams/{team}/{$}", ws.getTeam)
+
+	// push team updates as Server-Sent Events.
+	mwMux.HandleFunc("GET /teams/{team}/events", ws.streamEvents)
+	// refresh team automatically, a fallback for when events are unavailable.
+	mwMux.HandleFunc("GET /teams/{team}/game", ws.getGame)
 	// start a new game, with all players set to 0.
-	mwMux.HandleFunc("POST /game/start", ws.startGame)
+	mwMux.HandleFunc("POST /teams/{team}/game/start", ws.startGame)
 	// pause a game, subbing off players.
-	mwMux.HandleFunc("POST /game/pause", ws.pauseGame)
+	mwMux.HandleFunc("POST /teams/{team}/game/pause", ws.pauseGame)
 	// resume a game.
-	mwMux.HandleFunc("POST /game/resume", ws.resumeGame)
+	mwMux.HandleFunc("POST /teams/{team}/game/resume", ws.resumeGame)
 	// end a game without resetting player statistics.
-	mwMux.HandleFunc("POST /game/end", ws.endGame)
+	mwMux.HandleFunc("POST /teams/{team}/game/end", ws.endGame)
 	// reset game.
-	mwMux.HandleFunc("POST /game/reset", ws.resetGame)
+	mwMux.HandleFunc("POST /teams/{team}/game/reset", ws.resetGame)
+	// record points for the team, crediting an optional `scorer` and `assist`.
+	mwMux.HandleFunc("POST /teams/{team}/game/score", ws.score)
+	// record points for the opposition.
+	mwMux.HandleFunc("POST /teams/{team}/game/opponent-score", ws.opponentScore)
+
+	// undo or redo the most recent game or player action.
+	mwMux.HandleFunc("POST /teams/{team}/undo", ws.undo)
+	mwMux.HandleFunc("POST /teams/{team}/redo", ws.redo)
+
+	// game history, optionally as at a time of day `?at=15:04`.
+	mwMux.HandleFunc("GET /teams/{team}/history", ws.getHistory)
+	// archived games and player totals across them.
+	mwMux.HandleFunc("GET /teams/{team}/season", ws.getSeason)
 
 	// players
-	mwMux.HandleFunc("GET /players", ws.listPlayers)
-	mwMux.HandleFunc("POST /players/{name}/reset", ws.resetPlayer)
-	mwMux.HandleFunc("POST /players/{name}/set", ws.setPlayer)
-	mwMux.HandleFunc("POST /players/{name}/sub-on", ws.subOnPlayer)
-	mwMux.HandleFunc("POST /players/{name}/sub-off", ws.subOffPlayer)
+	mwMux.HandleFunc("GET /teams/{team}/players", ws.listPlayers)
+	mwMux.HandleFunc("POST /teams/{team}/players/{name}/reset", ws.resetPlayer)
+	mwMux.HandleFunc("POST /teams/{team}/players/{name}/set", ws.setPlayer)
+	// sub on a player, filling the position in the `position-{name}` form value.
+	mwMux.HandleFunc("POST /teams/{team}/players/{name}/sub-on", ws.subOnPlayer)
+	mwMux.HandleFunc("POST /teams/{team}/players/{name}/sub-off", ws.subOffPlayer)
+	// mark a player available or not this game, in the `availability-{name}` form value.
+	mwMux.HandleFunc("POST /teams/{team}/players/{name}/availability", ws.setAvailability)
+
+	// subs
+	// swap players, subbing off all `off` and subbing on all `on` at once.
+	mwMux.HandleFunc("POST /teams/{team}/subs/swap", ws.swap)
+
+	// spectator view through share links.
+	ws.setShareRoutes(mwMux)
+
+	// add, rename, renumber and remove players.
+	ws.setRosterRoutes(mwMux)
+
+	// statistics downloads and match reports.
+	ws.setExportRoutes(mwMux)
+
+	// JSON API
+	ws.setAPIRoutes(mwMux)
 
 	// static assets
 	mwMux.Handle("GET /robots.txt", ws.HandleStaticFiles())
@@ -43,90 +88,465 @@ func (ws *WebServer) setRoutes() {
 	mwMux.Handle("GET /static/", http.StripPrefix("/static", ws.HandleStaticFiles()))
 }
 
-func (ws *WebServer) home(w http.ResponseWriter, r *http.Request) {
+// teamPath returns the URL path prefix of all routes for the team.
+func teamPath(team string) string {
+	return "/teams/" + url.PathEscape(team)
+}
+
+// teamView is everything required to render a team's game and players.
+type teamView struct {
+	// Base is the URL path prefix for the team's routes.
+	Base    string
+	Game    Game
+	Players []Player
+	Undo    *Event
+	Redo    *Event
+	// Suggestions of swaps to even out play duration.
+	Suggestions []Swap
+	// OnField is the number of players playing, out of Capacity positions.
+	OnField  int
+	Capacity int
+	// Positions on the field, empty if unknown.
+	Positions []string
+	Format    GameFormat
+	// SubDue is when the next rolling sub is due, swapping Rolling players.
+	SubDue  time.Time
+	Rolling Swap
+	// Poll the server for updates while a game is underway.
+	Poll bool
+}
+
+// newTeamView returns the current state of the team ready for rendering.
+func newTeamView(s *Subber) teamView {
+	g := s.CurrentGame()
+
 	var poll bool
-	switch ws.subber.Game.State() {
+	switch g.State() {
 	case GameStateInProgress, GameStatePaused:
 		poll = true
 	default: // GameStateNotStarted, GameStateFinished
 	}
 
-	home := home(ws.subber.Game, ws.subber.ListPlayers(), poll)
-	tc := layout("Go Subs", "Manage team subs", home)
+	undo, redo := s.UndoRedo()
+	onField, capacity := s.FieldCapacity()
+
+	var rolling Swap
+
+	subDue := s.SubDue()
+	if !subDue.IsZero() {
+		rolling = s.RollingSub()
+	}
+
+	return teamView{
+		Base:        teamPath(s.Name()),
+		Game:        g,
+		Players:     s.ListPlayers(),
+		Undo:        undo,
+		Redo:        redo,
+		Suggestions: s.Suggest(),
+		OnField:     onField,
+		Capacity:    capacity,
+		Positions:   s.Positions(),
+		Format:      s.Format(),
+		SubDue:      subDue,
+		Rolling:     rolling,
+		Poll:        poll,
+	}
+}
+
+// periodLabel describes the current period, such as `Period 2/4`.
+func periodLabel(period, periods int) string {
+	if periods > 0 {
+		return fmt.Sprintf("Period %d/%d", period, periods)
+	}
+
+	return fmt.Sprintf("Period %d", period)
+}
+
+// playerNumber formats a player's number for a form, empty when unknown.
+func playerNumber(number int) string {
+	if number == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(number)
+}
+
+// fieldFull returns true when no more players can be subbed on.
+func (v teamView) fieldFull() bool {
+	return v.Capacity > 0 && v.OnField >= v.Capacity
+}
+
+// openPositions returns the positions no player is filling.
+func (v teamView) openPositions() []string {
+	var open []string
+
+	for _, pos := range v.Positions {
+		if !slices.ContainsFunc(v.Players, func(p Player) bool { return p.Playing && p.Position == pos }) {
+			open = append(open, pos)
+		}
+	}
+
+	return open
+}
+
+// openPositionsFor returns the open positions with the player's preferred
+// position first.
+func (v teamView) openPositionsFor(p Player) []string {
+	open := v.openPositions()
+	if i := slices.Index(open, p.PreferredPosition); i > 0 {
+		open = slices.Insert(slices.Delete(open, i, i+1), 0, p.PreferredPosition)
+	}
+
+	return open
+}
+
+// suggested returns true when the player is part of a suggested swap.
+func (v teamView) suggested(name string) bool {
+	for _, sw := range v.Suggestions {
+		if sw.Off == name || sw.On == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nextSub returns true when the player is part of the next rolling sub.
+func (v teamView) nextSub(name string) bool {
+	return !v.SubDue.IsZero() && (v.Rolling.Off == name || v.Rolling.On == name)
+}
+
+// findTeam returns the Subber for the named team.
+func (ws *WebServer) findTeam(name string) (*Subber, bool) {
+	for _, s := range ws.subbers {
+		if s.Name() == name {
+			return s, true
+		}
+	}
+
+	return nil, false
+}
+
+// team returns the Subber for the team in the request path, responding with an
+// error if there isn't one.
+func (ws *WebServer) team(w http.ResponseWriter, r *http.Request) (*Subber, bool) {
+	name := r.PathValue("team")
+
+	if s, ok := ws.findTeam(name); ok {
+		return s, true
+	}
+
+	ws.respondError(http.StatusNotFound, fmt.Errorf("team not found: %q", name), w, r)
+
+	return nil, false
+}
+
+// home shows the team picker, or the only team when there is just one.
+func (ws *WebServer) home(w http.ResponseWriter, r *http.Request) {
+	if len(ws.subbers) == 1 {
+		http.Redirect(w, r, teamPath(ws.subbers[0].Name())+"/", http.StatusSeeOther)
+
+		return
+	}
+
+	ws.listTeams(w, r)
+}
+
+// listTeams shows all teams to choose from.
+func (ws *WebServer) listTeams(w http.ResponseWriter, r *http.Request) {
+	names := make([]string, 0, len(ws.subbers))
+	for _, s := range ws.subbers {
+		names = append(names, s.Name())
+	}
+
+	tc := layout("Go Subs - Teams", "Choose a team", teams(names))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// getTeam shows the team's game and players.
+func (ws *WebServer) getTeam(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	tc := layout("Go Subs - "+s.Name(), "Manage team subs", home(newTeamView(s)))
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // getGame retrieves the current game.
 func (ws *WebServer) getGame(w http.ResponseWriter, r *http.Request) {
-	var poll bool
-	switch ws.subber.Game.State() {
-	case GameStateInProgress, GameStatePaused:
-		poll = true
-	default: // GameStateNotStarted, GameStateFinished
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
 	}
 
-	tc := game(ws.subber.Game, poll)
+	v := newTeamView(s)
+	tc := withUndoRedo(game(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // startGame starts a new game.
 func (ws *WebServer) startGame(w http.ResponseWriter, r *http.Request) {
-	ws.subber.StartGame()
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
 
-	var poll bool
-	switch ws.subber.Game.State() {
-	case GameStateInProgress, GameStatePaused:
-		poll = true
-	default: // GameStateNotStarted, GameStateFinished
+	if err := s.StartGame(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
 	}
 
-	tc := home(ws.subber.Game, ws.subber.ListPlayers(), poll)
+	tc := home(newTeamView(s))
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // pauseGame pauses the game, subbing off all players.
 func (ws *WebServer) pauseGame(w http.ResponseWriter, r *http.Request) {
-	ws.subber.PauseGame()
-	tc := home(ws.subber.Game, ws.subber.ListPlayers(), false)
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.PauseGame(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	v.Poll = false
+	tc := home(v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // resumeGame resumes the game.
 func (ws *WebServer) resumeGame(w http.ResponseWriter, r *http.Request) {
-	ws.subber.ResumeGame()
-	tc := game(ws.subber.Game, true)
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.ResumeGame(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	tc := withUndoRedo(game(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // endGame stops the game.
 func (ws *WebServer) endGame(w http.ResponseWriter, r *http.Request) {
-	ws.subber.EndGame()
-	tc := home(ws.subber.Game, ws.subber.ListPlayers(), false)
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.EndGame(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // resetGame stops the game.
 func (ws *WebServer) resetGame(w http.ResponseWriter, r *http.Request) {
-	ws.subber.ResetGame()
-	tc := home(ws.subber.Game, ws.subber.ListPlayers(), false)
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.ResetGame(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// score records points for the team.
+func (ws *WebServer) score(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing form: %v", err), w, r)
+
+		return
+	}
+
+	points, err := formPoints(r)
+	if err != nil {
+		ws.respondError(http.StatusBadRequest, err, w, r)
+
+		return
+	}
+
+	if err := s.Score(r.Form.Get("scorer"), r.Form.Get("assist"), points); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// opponentScore records points for the opposition.
+func (ws *WebServer) opponentScore(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing form: %v", err), w, r)
+
+		return
+	}
+
+	points, err := formPoints(r)
+	if err != nil {
+		ws.respondError(http.StatusBadRequest, err, w, r)
+
+		return
+	}
+
+	if err := s.OpponentScore(points); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// formPoints returns the points of a score, 1 when not provided.
+func formPoints(r *http.Request) (int, error) {
+	v := r.Form.Get("points")
+	if v == "" {
+		return 1, nil
+	}
+
+	points, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("parsing points: %v", err)
+	}
+
+	return points, nil
+}
+
+// undo reverts the most recent game or player action.
+func (ws *WebServer) undo(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.Undo(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// redo restores the most recently undone action.
+func (ws *WebServer) redo(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := s.Redo(); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	tc := home(newTeamView(s))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// getHistory lists game events and the players on the field at a time of day.
+func (ws *WebServer) getHistory(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	at := time.Now()
+
+	if v := r.URL.Query().Get("at"); v != "" {
+		clock, err := time.ParseInLocation("15:04", v, time.Local)
+		if err != nil {
+			ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing time: %v", err), w, r)
+
+			return
+		}
+
+		// times are relative to the day the current game started.
+		day := s.CurrentGame().StartTime
+		if day.IsZero() {
+			day = at
+		}
+
+		at = time.Date(day.Year(), day.Month(), day.Day(),
+			clock.Hour(), clock.Minute(), 0, 0, time.Local)
+	}
+
+	history := history(teamPath(s.Name()), s.Events(), at, s.PlayersAt(at))
+	tc := layout("Go Subs - History", "Game history", history)
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// getSeason shows player statistics totalled across archived games.
+func (ws *WebServer) getSeason(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	stats := s.Season()
+	tc := layout("Go Subs - Season", "Season statistics", season(teamPath(s.Name()), stats, seasonPositions(s.Positions(), stats), s.History()))
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // listPlayers returns all player statistics.
 func (ws *WebServer) listPlayers(w http.ResponseWriter, r *http.Request) {
-	var poll bool
-	switch ws.subber.Game.State() {
-	case GameStateInProgress, GameStatePaused:
-		poll = true
-	default: // GameStateNotStarted, GameStateFinished
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
 	}
 
-	tc := playerStatistics(ws.subber.ListPlayers(), poll)
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // resetPlayer play count and duration to zero.
 func (ws *WebServer) resetPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
 	ws.logger.Info("form data", "path", r.URL.EscapedPath(), "data", r.Form.Encode())
 
 	if err := r.ParseForm(); err != nil {
@@ -143,22 +563,25 @@ func (ws *WebServer) resetPlayer(w http.ResponseWriter, r *http.Request) {
 	}
 
 	for _, name := range names {
-		ws.subber.PlayerReset(name)
-	}
+		if err := s.PlayerReset(name); err != nil {
+			ws.respondError(errorStatus(err), err, w, r)
 
-	var poll bool
-	switch ws.subber.Game.State() {
-	case GameStateInProgress, GameStatePaused:
-		poll = true
-	default: // GameStateNotStarted, GameStateFinished
+			return
+		}
 	}
 
-	tc := playerStatistics(ws.subber.ListPlayers(), poll)
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // setPlayer to specified play count and duration.
 func (ws *WebServer) setPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
 	ws.logger.Info("form data", "path", r.URL.EscapedPath(), "data", r.Form.Encode())
 
 	if err := r.ParseForm(); err != nil {
@@ -171,7 +594,7 @@ func (ws *WebServer) setPlayer(w http.ResponseWriter, r *http.Request) {
 	playCounts := r.Form["playCount"]
 	playDurations := r.Form["playDuration"]
 
-	if len(names) != len(playCounts) && len(names) != len(playDurations) {
+	if len(names) != len(playCounts) || len(names) != len(playDurations) {
 		ws.respondError(http.StatusBadRequest, errors.New("all player values not provided"), w, r)
 
 		return
@@ -200,26 +623,35 @@ func (ws *WebServer) setPlayer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		ws.subber.PlayerSet(
+		if count < 0 || duration < 0 {
+			ws.respondError(http.StatusBadRequest, errors.New("play count and duration must not be negative"), w, r)
+
+			return
+		}
+
+		if err := s.PlayerSet(
 			names[idx],
 			count,
 			duration,
-		)
-	}
+		); err != nil {
+			ws.respondError(errorStatus(err), err, w, r)
 
-	var poll bool
-	switch ws.subber.Game.State() {
-	case GameStateInProgress, GameStatePaused:
-		poll = true
-	default: // GameStateNotStarted, GameStateFinished
+			return
+		}
 	}
 
-	tc := playerStatistics(ws.subber.ListPlayers(), poll)
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // subOnPlayer increasing play count and resuming play duration timer.
 func (ws *WebServer) subOnPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
 	name := r.PathValue("name")
 
 	if name == "" {
@@ -228,13 +660,30 @@ func (ws *WebServer) subOnPlayer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ws.subber.PlayerSubOn(name)
-	tc := subButton(name, true)
+	if err := r.ParseForm(); err != nil {
+		ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing form: %v", err), w, r)
+
+		return
+	}
+
+	if err := s.PlayerSubOnPosition(name, r.Form.Get("position-"+name)); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
 
 // subOffPlayer pausing play duration timer.
 func (ws *WebServer) subOffPlayer(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
 	name := r.PathValue("name")
 
 	if name == "" {
@@ -243,7 +692,68 @@ func (ws *WebServer) subOffPlayer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ws.subber.PlayerSubOff(name)
-	tc := subButton(name, false)
+	if err := s.PlayerSubOff(name); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// setAvailability marks a player available or not for this game.
+func (ws *WebServer) setAvailability(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing form: %v", err), w, r)
+
+		return
+	}
+
+	name := r.PathValue("name")
+	availability := Availability(r.Form.Get("availability-" + name))
+
+	if err := s.PlayerAvailability(name, availability); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// swap subs players off and on together.
+func (ws *WebServer) swap(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		ws.respondError(http.StatusBadRequest, fmt.Errorf("parsing form: %v", err), w, r)
+
+		return
+	}
+
+	// suggested swaps to or from an empty position send an empty name.
+	off := slices.DeleteFunc(r.Form["off"], func(name string) bool { return name == "" })
+	on := slices.DeleteFunc(r.Form["on"], func(name string) bool { return name == "" })
+
+	if err := s.Swap(off, on); err != nil {
+		ws.respondError(errorStatus(err), err, w, r)
+
+		return
+	}
+
+	v := newTeamView(s)
+	tc := withUndoRedo(playerStatistics(v), v)
 	ws.renderTemplate(http.StatusOK, tc, w, r)
 }
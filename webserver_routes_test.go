@@ -0,0 +1,186 @@
+package main
+
+import (
+	"html"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestWebServer_setPlayer(t *testing.T) {
+	tests := []struct {
+		name   string
+		form   string
+		status int
+		want   Player
+	}{
+		{
+			name:   "set",
+			form:   "playerName=jane&playCount=2&playDuration=10m",
+			status: http.StatusOK,
+			want:   Player{Name: "jane", Number: 1, PlayCount: 2, PlayDuration: 10 * time.Minute},
+		},
+		{
+			name:   "missing duration",
+			form:   "playerName=jane&playerName=john&playCount=2&playCount=3&playDuration=10m",
+			status: http.StatusBadRequest,
+			want:   Player{Name: "jane", Number: 1},
+		},
+		{
+			name:   "negative count",
+			form:   "playerName=jane&playCount=-1&playDuration=10m",
+			status: http.StatusBadRequest,
+			want:   Player{Name: "jane", Number: 1},
+		},
+		{
+			name:   "negative duration",
+			form:   "playerName=jane&playCount=2&playDuration=-10m",
+			status: http.StatusBadRequest,
+			want:   Player{Name: "jane", Number: 1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			s := newTestSubber(t, TeamConfig{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}})
+
+			ws := &WebServer{logger: slog.New(slog.NewTextHandler(io.Discard, nil)), subbers: []*Subber{s}}
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /teams/{team}/players/{name}/set", ws.setPlayer)
+
+			r := httptest.NewRequest(http.MethodPost, "/teams/tigers/players/jane/set", strings.NewReader(tc.form))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, r)
+
+			if w.Code != tc.status {
+				t.Errorf("status = %d, want %d", w.Code, tc.status)
+			}
+
+			want := []Player{tc.want, {Name: "john", Number: 2}}
+
+			ignore := cmpopts.IgnoreFields(Player{}, "RestStarted")
+			if diff := cmp.Diff(want, s.ListPlayers(), ignore); diff != "" {
+				t.Errorf("player mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestWebServer_playerPaths(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}}})
+
+	sharer, err := newSharer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := NewWebServer(slog.New(slog.NewTextHandler(io.Discard, nil)), ServerConfig{}, AuthConfig{}, nil, sharer, []*Subber{s})
+	if err != nil {
+		t.Fatalf("NewWebServer() error = %v", err)
+	}
+
+	srv := httptest.NewServer(ws.mux)
+	defer srv.Close()
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &http.Client{Jar: jar}
+
+	// get returns the page, failing the test unless it is found.
+	get := func(path string) string {
+		t.Helper()
+
+		resp, err := client.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s error = %v", path, err)
+		}
+		defer resp.Body.Close()
+
+		b, err := io.ReadAll(resp.Body)
+		if err != nil || resp.StatusCode != http.StatusOK {
+			t.Fatalf("GET %s = %d, %v, want %d", path, resp.StatusCode, err, http.StatusOK)
+		}
+
+		return string(b)
+	}
+
+	// find returns the first submatch of the pattern in the page.
+	find := func(page, pattern string) string {
+		t.Helper()
+
+		m := regexp.MustCompile(pattern).FindStringSubmatch(page)
+		if m == nil {
+			t.Fatalf("%s not found in page:\n%s", pattern, page)
+		}
+
+		return html.UnescapeString(m[1])
+	}
+
+	const name = "jo #1? 50%"
+
+	roster := get("/teams/tigers/roster")
+	form := url.Values{
+		"name":        {name},
+		"number":      {"1"},
+		csrfFieldName: {find(roster, `name="csrf_token" value="([^"]*)"`)},
+	}
+
+	resp, err := client.PostForm(srv.URL+find(roster, `action="(/teams/tigers/roster/jane)"`), form)
+	if err != nil {
+		t.Fatalf("renaming error = %v", err)
+	}
+	resp.Body.Close()
+
+	// the rename redirects back to the roster.
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("renaming status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+
+	// the renamed player's button subs them on, rather than a player named
+	// "jo " or a path that doesn't exist.
+	subOn := find(get("/teams/tigers/"), `hx-post="([^"]*/sub-on)"`)
+
+	r, err := http.NewRequest(http.MethodPost, srv.URL+subOn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.Header.Set("HX-Request", "true")
+	r.Header.Set(csrfHeaderName, form.Get(csrfFieldName))
+
+	resp, err = client.Do(r)
+	if err != nil {
+		t.Fatalf("POST %s error = %v", subOn, err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("POST %s = %d, want %d", subOn, resp.StatusCode, http.StatusOK)
+	}
+
+	want := []Player{{Name: name, Number: 1, PlayCount: 1, Playing: true}}
+
+	ignore := cmpopts.IgnoreFields(Player{}, "PlayDuration", "PlayStarted", "RestStarted", "PositionDurations")
+	if diff := cmp.Diff(want, s.ListPlayers(), ignore); diff != "" {
+		t.Errorf("ListPlayers() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// setShareRoutes registers the spectator view, a read only team page reached
+// through a share link so parents can follow the game.
+func (ws *WebServer) setShareRoutes(mux *http.ServeMux) {
+	// share links for a coach to send to parents.
+	mux.HandleFunc("GET /teams/{team}/share", ws.getShareLinks)
+
+	mux.HandleFunc("GET /share/{token}/{$}", ws.spectator(ws.getShare))
+	mux.HandleFunc("GET /share/{token}/events", ws.spectator(ws.streamShare))
+	mux.HandleFunc("GET /share/{token}/game", ws.spectator(ws.getShareGame))
+	mux.HandleFunc("GET /share/{token}/players", ws.spectator(ws.getSharePlayers))
+}
+
+// sharePath returns the URL path prefix of all routes for the share token.
+func sharePath(token string) string {
+	return "/share/" + url.PathEscape(token)
+}
+
+// shareLink is a spectator link for a team, or one of its players.
+type shareLink struct {
+	// Player the link shows, empty for the whole team.
+	Player string
+	URL    string
+}
+
+// spectator renders next as a read only page, even for a logged in coach.
+func (ws *WebServer) spectator(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		next(w, r.WithContext(withViewer(r.Context(), viewer{})))
+	}
+}
+
+// share returns the Subber and payload of the token in the request path,
+// responding with an error if the token isn't valid.
+func (ws *WebServer) share(w http.ResponseWriter, r *http.Request) (*Subber, sharePayload, bool) {
+	shared, err := ws.sharer.parse(r.PathValue("token"))
+	if err != nil {
+		ws.respondError(http.StatusNotFound, err, w, r)
+
+		return nil, sharePayload{}, false
+	}
+
+	s, ok := ws.findTeam(shared.Team)
+	if !ok {
+		ws.respondError(http.StatusNotFound, fmt.Errorf("team not found: %q", shared.Team), w, r)
+
+		return nil, sharePayload{}, false
+	}
+
+	return s, shared, true
+}
+
+// newShareView returns the team view for a share link, with only the player
+// when the link is for a single player. The player is followed through renames
+// since the link was made, and no longer shown once removed.
+func newShareView(s *Subber, token string, shared sharePayload) teamView {
+	v := newTeamView(s)
+	v.Base = sharePath(token)
+	v.Undo, v.Redo = nil, nil
+	v.Suggestions = nil
+
+	if shared.Player != "" {
+		name := s.PlayerName(shared.Player, shared.Issued)
+
+		players := make([]Player, 0, 1)
+		for _, p := range v.Players {
+			if p.Name == name {
+				players = append(players, p)
+			}
+		}
+
+		v.Players = players
+	}
+
+	return v
+}
+
+// getShareLinks lists share links for the team and each of its players.
+func (ws *WebServer) getShareLinks(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	// player links would reveal every player's minutes to parents.
+	if !canEdit(r.Context()) {
+		ws.respondError(http.StatusForbidden, errors.New("only coaches can share links"), w, r)
+
+		return
+	}
+
+	scheme := "http"
+	if ws.tls {
+		scheme = "https"
+	}
+
+	now := time.Now()
+
+	link := func(player string) shareLink {
+		u := url.URL{Scheme: scheme, Host: r.Host, Path: sharePath(ws.sharer.token(s.Name(), player, now)) + "/"}
+
+		return shareLink{Player: player, URL: u.String()}
+	}
+
+	players := s.ListPlayers()
+	links := make([]shareLink, 0, len(players))
+
+	for _, p := range players {
+		links = append(links, link(p.Name))
+	}
+
+	tc := layout("Go Subs - Share", "Share links", shareLinks(s.Name(), link(""), links))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// getShare shows the game and players of a share link.
+func (ws *WebServer) getShare(w http.ResponseWriter, r *http.Request) {
+	s, shared, ok := ws.share(w, r)
+	if !ok {
+		return
+	}
+
+	tc := layout("Go Subs - "+s.Name(), "Follow the game", spectate(newShareView(s, r.PathValue("token"), shared)))
+	ws.renderTemplate(http.StatusOK, tc, w, r)
+}
+
+// streamShare pushes the game and players of a share link as Server-Sent Events.
+func (ws *WebServer) streamShare(w http.ResponseWriter, r *http.Request) {
+	s, shared, ok := ws.share(w, r)
+	if !ok {
+		return
+	}
+
+	ws.stream(w, r, s, func() []sseFragment {
+		v := newShareView(s, r.PathValue("token"), shared)
+
+		return []sseFragment{
+			{"game", game(v)},
+			{"players", playerStatistics(v)},
+		}
+	})
+}
+
+// getShareGame refreshes the game of a share link.
+func (ws *WebServer) getShareGame(w http.ResponseWriter, r *http.Request) {
+	s, shared, ok := ws.share(w, r)
+	if !ok {
+		return
+	}
+
+	ws.renderTemplate(http.StatusOK, game(newShareView(s, r.PathValue("token"), shared)), w, r)
+}
+
+// getSharePlayers refreshes the players of a share link.
+func (ws *WebServer) getSharePlayers(w http.ResponseWriter, r *http.Request) {
+	s, shared, ok := ws.share(w, r)
+	if !ok {
+		return
+	}
+
+	ws.renderTemplate(http.StatusOK, playerStatistics(newShareView(s, r.PathValue("token"), shared)), w, r)
+}
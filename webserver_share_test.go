@@ -0,0 +1,92 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWebServer_shareRenamedPlayer(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}, {Name: "john", Number: 2}}})
+
+	sharer, err := newSharer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, err := NewWebServer(slog.New(slog.NewTextHandler(io.Discard, nil)), ServerConfig{}, AuthConfig{}, nil, sharer, []*Subber{s})
+	if err != nil {
+		t.Fatalf("NewWebServer() error = %v", err)
+	}
+
+	path := sharePath(sharer.token("tigers", "jane", time.Now())) + "/players"
+
+	// players returns which of the names the share link shows.
+	players := func(names ...string) []string {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		ws.mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d, want %d", path, w.Code, http.StatusOK)
+		}
+
+		var shown []string
+
+		for _, name := range names {
+			if strings.Contains(w.Body.String(), name) {
+				shown = append(shown, name)
+			}
+		}
+
+		return shown
+	}
+
+	if diff := cmp.Diff([]string{"jane"}, players("jane", "john")); diff != "" {
+		t.Errorf("players mismatch (-want +got):\n%s", diff)
+	}
+
+	// the link follows jane, rather than a new player given the old name.
+	if err := s.UpdatePlayer("jane", "zoe", 1); err != nil {
+		t.Fatalf("UpdatePlayer() error = %v", err)
+	}
+
+	if err := s.AddPlayer("jane", 3); err != nil {
+		t.Fatalf("AddPlayer() error = %v", err)
+	}
+
+	if diff := cmp.Diff([]string{"zoe"}, players("jane", "john", "zoe")); diff != "" {
+		t.Errorf("players after rename mismatch (-want +got):\n%s", diff)
+	}
+
+	// undoing the new player and the rename shows jane again.
+	for range 2 {
+		if err := s.Undo(); err != nil {
+			t.Fatalf("Undo() error = %v", err)
+		}
+	}
+
+	if diff := cmp.Diff([]string{"jane"}, players("jane", "john", "zoe")); diff != "" {
+		t.Errorf("players after undo mismatch (-want +got):\n%s", diff)
+	}
+
+	// a removed player is no longer shown, nor is anyone else.
+	if err := s.RemovePlayer("jane"); err != nil {
+		t.Fatalf("RemovePlayer() error = %v", err)
+	}
+
+	if err := s.AddPlayer("jane", 3); err != nil {
+		t.Fatalf("AddPlayer() error = %v", err)
+	}
+
+	if got := players("jane", "john"); len(got) != 0 {
+		t.Errorf("players after removal = %v, want none", got)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+)
+
+// sseFragment is a component replacing the element with the id of the event.
+type sseFragment struct {
+	event string
+	tc    templ.Component
+}
+
+// streamEvents pushes the team's game, undo and player fragments to the client
+// using Server-Sent Events, immediately on connecting and then whenever the
+// team changes. Each event is named after the id of the element it replaces.
+func (ws *WebServer) streamEvents(w http.ResponseWriter, r *http.Request) {
+	s, ok := ws.team(w, r)
+	if !ok {
+		return
+	}
+
+	ws.stream(w, r, s, func() []sseFragment {
+		v := newTeamView(s)
+
+		return []sseFragment{
+			{"game", game(v)},
+			{"undo-redo", undoRedo(v, false)},
+			{"players", playerStatistics(v)},
+		}
+	})
+}
+
+// stream sends the fragments rendered by render whenever the team changes,
+// until the client disconnects or the server shuts down.
+func (ws *WebServer) stream(w http.ResponseWriter, r *http.Request, s *Subber, render func() []sseFragment) {
+	rc := http.NewResponseController(w)
+
+	// streams outlive the server's write timeout.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		ws.respondError(http.StatusInternalServerError, fmt.Errorf("clearing write deadline: %w", err), w, r)
+
+		return
+	}
+
+	updates, unsubscribe := s.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	send := func() error {
+		for _, f := range render() {
+			if err := writeSSE(r.Context(), w, f.event, f.tc); err != nil {
+				return err
+			}
+		}
+
+		return rc.Flush()
+	}
+
+	if err := send(); err != nil {
+		ws.logger.Error("stream()", "error", err.Error())
+
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatSeconds * time.Second)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ws.shutdown:
+			return
+		case <-updates:
+			if err := send(); err != nil {
+				ws.logger.Error("stream()", "error", err.Error())
+
+				return
+			}
+		case <-heartbeat.C:
+			// comments keep the connection open through proxies and detect
+			// clients that have gone away.
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeSSE renders the component as the data of a named event.
+func writeSSE(ctx context.Context, w http.ResponseWriter, event string, tc templ.Component) error {
+	var buf bytes.Buffer
+	if err := tc.Render(ctx, &buf); err != nil {
+		return fmt.Errorf("rendering %s: %w", event, err)
+	}
+
+	var sb strings.Builder
+
+	sb.WriteString("event: " + event + "\n")
+
+	for _, line := range strings.Split(buf.String(), "\n") {
+		sb.WriteString("data: " + line + "\n")
+	}
+
+	sb.WriteString("\n")
+
+	_, err := fmt.Fprint(w, sb.String())
+
+	return err
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// readEvents returns the names of the next n events in the stream.
+func readEvents(t *testing.T, r *bufio.Reader, n int) []string {
+	t.Helper()
+
+	var events []string
+
+	for len(events) < n {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+
+		if event, ok := strings.CutPrefix(line, "event: "); ok {
+			events = append(events, strings.TrimSpace(event))
+		}
+	}
+
+	return events
+}
+
+// subscribers returns the number of streams subscribed to the team.
+func subscribers(s *Subber) int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.subscribers)
+}
+
+func TestWebServer_streamEvents(t *testing.T) {
+	s := newTestSubber(t, TeamConfig{Name: "tigers", Players: []Player{{Name: "jane", Number: 1}}})
+
+	ws := &WebServer{
+		logger:   slog.New(slog.NewTextHandler(io.Discard, nil)),
+		subbers:  []*Subber{s},
+		shutdown: make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /teams/{team}/events", ws.streamEvents)
+
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	connect := func(ctx context.Context) *bufio.Reader {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/teams/tigers/events", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+
+		t.Cleanup(func() { resp.Body.Close() })
+
+		if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+			t.Fatalf("Content-Type = %q, want text/event-stream", got)
+		}
+
+		return bufio.NewReader(resp.Body)
+	}
+
+	leaving, leave := context.WithCancel(ctx)
+	clients := []*bufio.Reader{connect(ctx), connect(leaving)}
+
+	want := []string{"game", "undo-redo", "players"}
+
+	// every client is sent the page on connecting, and again after a change.
+	for _, c := range clients {
+		if diff := cmp.Diff(want, readEvents(t, c, len(want))); diff != "" {
+			t.Errorf("connect events mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	if err := s.StartGame(); err != nil {
+		t.Fatalf("StartGame() error = %v", err)
+	}
+
+	for _, c := range clients {
+		if diff := cmp.Diff(want, readEvents(t, c, len(want))); diff != "" {
+			t.Errorf("notify events mismatch (-want +got):\n%s", diff)
+		}
+	}
+
+	// a client going away unsubscribes.
+	leave()
+
+	for subscribers(s) != 1 {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("subscribers = %d after client disconnected, want 1", subscribers(s))
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+
+	// shutting down ends the remaining stream.
+	close(ws.shutdown)
+
+	if _, err := io.ReadAll(clients[0]); err != nil && !errors.Is(err, io.EOF) {
+		t.Errorf("reading stream after shutdown error = %v, want end of stream", err)
+	}
+
+	if got := subscribers(s); got != 0 {
+		t.Errorf("subscribers = %d after shutdown, want 0", got)
+	}
+}